@@ -0,0 +1,126 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Birthday is a birthday or an anniversary, that is shown every year
+type Birthday struct {
+	Name  string
+	Month time.Month
+	Day   int
+	Year  int // the year of birth, or 0 if it is not known
+}
+
+// LeapDayPolicy decides on which day birthdays on the 29th of February are shown in other years
+type LeapDayPolicy string
+
+const (
+	LeapDayFeb28 LeapDayPolicy = "feb28" // on the 28th of February, the default
+	LeapDayMar1  LeapDayPolicy = "mar1"  // on the 1st of March
+)
+
+// ParseLeapDayPolicy parses "feb28" or "mar1". An empty string gives the default policy.
+func ParseLeapDayPolicy(s string) (LeapDayPolicy, error) {
+	switch policy := LeapDayPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case "", LeapDayFeb28:
+		return LeapDayFeb28, nil
+	case LeapDayMar1:
+		return policy, nil
+	}
+	return LeapDayFeb28, fmt.Errorf("unknown leap day policy, expected feb28 or mar1: %s", s)
+}
+
+// ParseBirthdays reads birthdays, one on each line, with the date first and then the name, like:
+//
+//	2013-05-17 Alice
+//	03-08 Grandma
+//
+// The year of birth is optional. Empty lines and lines starting with # are skipped.
+func ParseBirthdays(r io.Reader) ([]Birthday, error) {
+	var birthdays []Birthday
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		date, name, _ := strings.Cut(line, " ")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("line %d: missing name: %s", lineNumber, line)
+		}
+		b := Birthday{Name: name}
+		if t, err := time.Parse("2006-01-02", date); err == nil {
+			b.Year, b.Month, b.Day = t.Date()
+		} else if t, err := time.Parse("01-02", date); err == nil {
+			b.Month, b.Day = t.Month(), t.Day()
+		} else {
+			return nil, fmt.Errorf("line %d: invalid date, expected YYYY-MM-DD or MM-DD: %s", lineNumber, date)
+		}
+		birthdays = append(birthdays, b)
+	}
+	return birthdays, scanner.Err()
+}
+
+// LoadBirthdays reads birthdays from the given file, see ParseBirthdays
+func LoadBirthdays(filename string) ([]Birthday, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	birthdays, err := ParseBirthdays(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return birthdays, nil
+}
+
+// dateIn returns the day that the birthday is shown in the given year, at midnight UTC
+func (b Birthday) dateIn(year int, policy LeapDayPolicy) time.Time {
+	if b.Month == time.February && b.Day == 29 && daysIn(year, time.February) == 28 {
+		if policy == LeapDayMar1 {
+			return time.Date(year, time.March, 1, 0, 0, 0, 0, time.UTC)
+		}
+		return time.Date(year, time.February, 28, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(year, b.Month, b.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// age returns the age in the given year, like "12 years", or an empty string if the year of birth is not known
+func (b Birthday) age(year int) string {
+	if age := year - b.Year; b.Year > 0 && age > 0 {
+		return FormatAge(age)
+	}
+	return ""
+}
+
+// text returns the name, followed by the age in the given year, if the year of birth is known
+func (b Birthday) text(year int) string {
+	return strings.TrimSpace(b.Name + " " + b.age(year))
+}
+
+// addBirthdays adds a note to the days with birthdays, and a cake to the row of the person,
+// if one of the names is the name of the person
+func addBirthdays(w *Week, birthdays []Birthday, policy LeapDayPolicy, names []string) {
+	for i := range w.Days {
+		day := &w.Days[i]
+		for _, b := range birthdays {
+			if !b.dateIn(day.Time.Year(), policy).Equal(day.Time) {
+				continue
+			}
+			day.Notes = append(day.Notes, b.text(day.Time.Year()))
+			for row, name := range names {
+				if strings.EqualFold(strings.TrimSpace(name), b.Name) {
+					day.Cells[row].Lines = append(day.Cells[row].Lines, Line{Text: b.age(day.Time.Year()), Icon: IconCake})
+				}
+			}
+		}
+	}
+}
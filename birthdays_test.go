@@ -0,0 +1,95 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParseBirthdays(t *testing.T) {
+	birthdays, err := ParseBirthdays(strings.NewReader("# Family\n2013-05-14 Alice\n\n03-08  Grandma Anne\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(birthdays) != 2 {
+		t.Fatalf("expected 2 birthdays, got %+v", birthdays)
+	}
+	if b := birthdays[0]; b.Name != "Alice" || b.Year != 2013 || b.Month != time.May || b.Day != 14 {
+		t.Errorf("unexpected birthday: %+v", b)
+	}
+	if b := birthdays[1]; b.Name != "Grandma Anne" || b.Year != 0 || b.Month != time.March || b.Day != 8 {
+		t.Errorf("unexpected birthday: %+v", b)
+	}
+	if _, err := ParseBirthdays(strings.NewReader("2013-05-14 Alice\n17.05 Bob\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected an error for line 2, got %v", err)
+	}
+	if _, err := ParseBirthdays(strings.NewReader("2013-05-14\n")); err == nil {
+		t.Error("expected an error for a missing name")
+	}
+}
+
+func TestBirthdayDateIn(t *testing.T) {
+	leapling := Birthday{Name: "Mallory", Month: time.February, Day: 29, Year: 2016}
+	for _, tc := range []struct {
+		year   int
+		policy LeapDayPolicy
+		want   string
+	}{
+		{2024, LeapDayFeb28, "2024-02-29"},
+		{2024, LeapDayMar1, "2024-02-29"},
+		{2025, LeapDayFeb28, "2025-02-28"},
+		{2025, LeapDayMar1, "2025-03-01"},
+		{2100, LeapDayMar1, "2100-03-01"},
+	} {
+		if got := leapling.dateIn(tc.year, tc.policy).Format("2006-01-02"); got != tc.want {
+			t.Errorf("expected %s in %d with %s, got %s", tc.want, tc.year, tc.policy, got)
+		}
+	}
+	if _, err := ParseLeapDayPolicy("mar2"); err == nil {
+		t.Error("expected an error for an unknown leap day policy")
+	}
+}
+
+func TestAddBirthdays(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	birthdays := []Birthday{
+		{Name: "Alice", Month: time.May, Day: 17, Year: 2013},
+		{Name: "Grandma", Month: time.May, Day: 14},
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 20, Names: []string{"Bob", "Alice"}, Birthdays: birthdays})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The holiday names are only shown if they are asked for
+	if day := page.Weeks[0].Days[5]; len(day.Notes) != 1 || day.Notes[0] != "Alice "+FormatAge(12) {
+		t.Errorf("expected only the birthday in the notes, got %q", day.Notes)
+	}
+	page, err = buildPage(cal, tmpl, Options{Year: 2025, Week: 20, Names: []string{"Bob", "Alice"}, Birthdays: birthdays, HolidayNotes: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The birthday is listed after the holiday name, in the same notes
+	day := page.Weeks[0].Days[5]
+	if len(day.Notes) != 2 || day.Notes[0] != day.Holiday || day.Notes[1] != "Alice "+FormatAge(12) {
+		t.Errorf("expected the holiday and the birthday in the notes, got %q", day.Notes)
+	}
+	if lines := day.Cells[1].Lines; len(lines) != 1 || lines[0].Icon != IconCake || lines[0].Text != FormatAge(12) {
+		t.Errorf("expected a cake in the row of Alice, got %+v", lines)
+	}
+	if lines := day.Cells[0].Lines; len(lines) != 0 {
+		t.Errorf("expected no lines in the row of Bob, got %+v", lines)
+	}
+	// Grandma has no row and no known year of birth
+	if day := page.Weeks[0].Days[2]; len(day.Notes) != 1 || day.Notes[0] != "Grandma" {
+		t.Errorf("expected only the name of Grandma in the notes, got %q", day.Notes)
+	}
+}
@@ -0,0 +1,229 @@
+package kitchencalendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// Chore is a household task that the people take turns doing, like doing the dishes
+type Chore struct {
+	Name        string
+	People      []string       // the names that take turns, in order, or all rows if empty
+	Start       time.Time      // the first day of the rotation, where the first person has the chore
+	Weekdays    []time.Weekday // the chore is only done on these weekdays, if any
+	Every       int            // the chore is only done every N days, counted from the start, if more than 1
+	SkipRedDays bool           // the chore is not done on Sundays and public holidays
+}
+
+// choreFile is how a chore is written in a chores file
+type choreFile struct {
+	Name        string   `json:"name"`
+	People      []string `json:"people,omitempty"`
+	Start       string   `json:"start"`
+	Weekdays    []string `json:"weekdays,omitempty"`
+	Every       int      `json:"every,omitempty"`
+	SkipRedDays bool     `json:"skipRedDays,omitempty"`
+}
+
+// parseWeekday parses an English weekday name, like "monday", or a two-letter abbreviation, like "MO"
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		if strings.ToUpper(weekday.String()) == s {
+			return weekday, nil
+		}
+	}
+	if weekday, ok := rruleWeekdays[s]; ok {
+		return weekday, nil
+	}
+	return time.Sunday, fmt.Errorf("unknown weekday: %s", s)
+}
+
+// hasRow checks if the given name is one of the names of the rows, ignoring case and surrounding spaces
+func hasRow(names []string, name string) bool {
+	for _, row := range names {
+		if strings.EqualFold(strings.TrimSpace(row), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseChores parses a JSON list of chores, like:
+//
+//	[{"name": "Dishes", "people": ["Alice", "Bob"], "start": "2025-01-06", "skipRedDays": true},
+//	 {"name": "Trash", "weekdays": ["tuesday", "friday"], "start": "2025-01-06"},
+//	 {"name": "Vacuum", "every": 3, "start": "2025-01-06"}]
+//
+// The people of a chore must be among the given names of the rows, if any names are given.
+func ParseChores(data []byte, names []string) ([]Chore, error) {
+	var files []choreFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+	chores := make([]Chore, 0, len(files))
+	for _, f := range files {
+		if f.Name == "" {
+			return nil, errors.New("a chore has no name")
+		}
+		if f.Start == "" {
+			return nil, fmt.Errorf("the chore %q has no start date", f.Name)
+		}
+		start, err := time.Parse("2006-01-02", f.Start)
+		if err != nil {
+			return nil, fmt.Errorf("the chore %q has an invalid start date: %w", f.Name, err)
+		}
+		if f.Every < 0 {
+			return nil, fmt.Errorf("the chore %q has a negative interval", f.Name)
+		}
+		for _, person := range f.People {
+			if len(names) > 0 && !hasRow(names, person) {
+				return nil, fmt.Errorf("the chore %q: %q is not one of the names: %s", f.Name, person, strings.Join(names, ", "))
+			}
+		}
+		c := Chore{Name: f.Name, People: f.People, Start: start, Every: f.Every, SkipRedDays: f.SkipRedDays}
+		for _, s := range f.Weekdays {
+			weekday, err := parseWeekday(s)
+			if err != nil {
+				return nil, fmt.Errorf("the chore %q: %w", f.Name, err)
+			}
+			c.Weekdays = append(c.Weekdays, weekday)
+		}
+		chores = append(chores, c)
+	}
+	return chores, nil
+}
+
+// LoadChores reads a JSON list of chores for the given names from the given file, see ParseChores
+func LoadChores(filename string, names []string) ([]Chore, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	chores, err := ParseChores(data, names)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return chores, nil
+}
+
+// regular checks if the chore is done on the given day, the given number of days after the start,
+// by the weekdays and the interval alone. With SkipRedDays, Sundays are also left out.
+func (c Chore) regular(t time.Time, days int) bool {
+	if c.Every > 1 && days%c.Every != 0 {
+		return false
+	}
+	if c.SkipRedDays && t.Weekday() == time.Sunday {
+		return false
+	}
+	if len(c.Weekdays) == 0 {
+		return true
+	}
+	for _, weekday := range c.Weekdays {
+		if weekday == t.Weekday() {
+			return true
+		}
+	}
+	return false
+}
+
+// daysSince returns the number of days from the start of the rotation to the given day, at midnight UTC
+func (c Chore) daysSince(t time.Time) int {
+	return int(math.Round(t.Sub(dateOf(c.Start)).Hours() / 24))
+}
+
+// due checks if the chore is done on the given day, at midnight UTC
+func (c Chore) due(cal kal.Calendar, t time.Time) bool {
+	days := c.daysSince(t)
+	if days < 0 || !c.regular(t, days) {
+		return false
+	}
+	return !c.SkipRedDays || !isRedDay(cal, t)
+}
+
+// turnsBefore returns the number of turns that are used up before the given day, at midnight UTC.
+// The weekdays and the interval repeat every 7 times the interval days, so whole periods are
+// counted at once. Only the public holidays that are not on a Sunday are looked up one by one,
+// on the days where the chore would otherwise be done.
+func (c Chore) turnsBefore(cal kal.Calendar, t time.Time) int {
+	days := c.daysSince(t)
+	if days <= 0 {
+		return 0
+	}
+	start := dateOf(c.Start)
+	period := 7 * max(c.Every, 1)
+	full, rest := days/period, days%period
+	turns := 0
+	for i := 0; i < period; i++ {
+		if c.regular(start.AddDate(0, 0, i), i) {
+			turns += full
+			if i < rest {
+				turns++
+			}
+		}
+	}
+	if c.SkipRedDays {
+		step := max(c.Every, 1)
+		for i := 0; i < days; i += step {
+			if d := start.AddDate(0, 0, i); c.regular(d, i) && kal.RedDay(cal, d) {
+				turns--
+			}
+		}
+	}
+	return turns
+}
+
+// Turns returns who has the chore on each day that it is done, from the given date to the given
+// date (inclusive), by the date on the form YYYY-MM-DD. The people take turns in order, counted
+// from the start of the rotation, so that the rotation continues correctly from one page to the
+// next, also when the pages are generated separately. Skipped red days do not use up a turn.
+func (c Chore) Turns(cal kal.Calendar, people []string, from, to time.Time) map[string]string {
+	turns := make(map[string]string)
+	if len(c.People) > 0 {
+		people = c.People
+	}
+	if len(people) == 0 {
+		return turns
+	}
+	from, to = dateOf(from), dateOf(to)
+	if start := dateOf(c.Start); from.Before(start) {
+		from = start
+	}
+	turn := c.turnsBefore(cal, from)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !c.due(cal, d) {
+			continue
+		}
+		turns[d.Format("2006-01-02")] = people[turn%len(people)]
+		turn++
+	}
+	return turns
+}
+
+// addChores adds a line with a checkbox to the cell of the person that has each chore on each day
+func addChores(cal kal.Calendar, w *Week, chores []Chore, names []string) {
+	if len(w.Days) == 0 {
+		return
+	}
+	for _, c := range chores {
+		turns := c.Turns(cal, names, w.Days[0].Time, w.Days[len(w.Days)-1].Time)
+		for i, day := range w.Days {
+			person, ok := turns[day.Date]
+			if !ok {
+				continue
+			}
+			for row, name := range names {
+				if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(person)) {
+					w.Days[i].Cells[row].Lines = append(w.Days[i].Cells[row].Lines, Line{Text: c.Name, Checkbox: true})
+				}
+			}
+		}
+	}
+}
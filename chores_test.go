@@ -0,0 +1,142 @@
+package kitchencalendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParseChores(t *testing.T) {
+	chores, err := ParseChores([]byte(`[
+		{"name": "Dishes", "people": ["Bob", "Alice"], "start": "2025-12-29", "skipRedDays": true},
+		{"name": "Trash", "weekdays": ["tuesday", "FR"], "start": "2025-12-29", "every": 0}
+	]`), []string{"Alice", " bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chores) != 2 || !chores[0].SkipRedDays || len(chores[1].Weekdays) != 2 || chores[1].Weekdays[1] != time.Friday {
+		t.Errorf("unexpected chores: %+v", chores)
+	}
+	for _, data := range []string{
+		`[{"name": "Dishes"}]`,
+		`[{"start": "2025-12-29"}]`,
+		`[{"name": "Dishes", "start": "29.12.2025"}]`,
+		`[{"name": "Dishes", "start": "2025-12-29", "weekdays": ["someday"]}]`,
+	} {
+		if _, err := ParseChores([]byte(data), nil); err == nil {
+			t.Errorf("expected an error for %s", data)
+		}
+	}
+	if _, err := ParseChores([]byte(`[{"name": "Dishes", "people": ["Bob", "Alcie"], "start": "2025-12-29"}]`), []string{"Bob", "Alice"}); err == nil {
+		t.Error("expected an error for a person that is not one of the names")
+	}
+}
+
+func TestChoreTurns(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"Bob", "Alice", "Mallory"}
+	start := time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)
+	day := func(s string) time.Time {
+		d, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	// The rotation continues across the new year, also when the weeks are computed separately
+	daily := Chore{Name: "Dishes", Start: start}
+	all := daily.Turns(cal, names, start, day("2026-01-11"))
+	later := daily.Turns(cal, names, day("2026-01-05"), day("2026-01-11"))
+	for date, person := range later {
+		if all[date] != person {
+			t.Errorf("expected %s to have the chore on %s, got %s", all[date], date, person)
+		}
+	}
+	if len(later) != 7 || later["2026-01-05"] != "Alice" || later["2026-01-06"] != "Mallory" {
+		t.Errorf("unexpected turns: %v", later)
+	}
+
+	// The 1st of January and the sundays are skipped, without using up a turn
+	skipping := Chore{Name: "Dishes", People: []string{"Bob", "Alice"}, Start: start, SkipRedDays: true}
+	turns := skipping.Turns(cal, names, start, day("2026-01-05"))
+	for date, person := range map[string]string{"2025-12-31": "Bob", "2026-01-02": "Alice", "2026-01-03": "Bob", "2026-01-05": "Alice"} {
+		if turns[date] != person {
+			t.Errorf("expected %s to have the chore on %s, got %q", person, date, turns[date])
+		}
+	}
+	if _, ok := turns["2026-01-01"]; ok {
+		t.Error("expected no chore on the 1st of January")
+	}
+	if _, ok := turns["2026-01-04"]; ok {
+		t.Error("expected no chore on a sunday")
+	}
+
+	// Every third day, and only on the given weekdays
+	every := Chore{Name: "Vacuum", Start: start, Every: 3}
+	if turns := every.Turns(cal, names, day("2026-01-01"), day("2026-01-04")); len(turns) != 2 || turns["2026-01-01"] != "Alice" || turns["2026-01-04"] != "Mallory" {
+		t.Errorf("unexpected turns every third day: %v", turns)
+	}
+	weekly := Chore{Name: "Trash", Start: start, Weekdays: []time.Weekday{time.Friday}}
+	if turns := weekly.Turns(cal, names, day("2026-01-05"), day("2026-01-18")); len(turns) != 2 || turns["2026-01-09"] != "Alice" || turns["2026-01-16"] != "Mallory" {
+		t.Errorf("unexpected turns on fridays: %v", turns)
+	}
+	if turns := weekly.Turns(cal, names, day("2025-12-01"), day("2025-12-28")); len(turns) != 0 {
+		t.Errorf("expected no turns before the start, got %v", turns)
+	}
+}
+
+func TestAddChores(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chores := []Chore{{Name: "Dishes", People: []string{"Alice", "Bob"}, Start: time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)}}
+	page, err := buildPage(cal, tmpl, Options{Year: 2026, Week: 2, Names: []string{"Bob", "Alice"}, Chores: chores})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The 29th of December was Alice's turn, so the 5th of January is Bob's turn
+	lines := page.Weeks[0].Days[0].Cells[0].Lines
+	if len(lines) != 1 || lines[0].Text != "Dishes" || !lines[0].Checkbox {
+		t.Errorf("expected Bob to have the dishes on the 5th of January, got %+v", page.Weeks[0].Days[0].Cells)
+	}
+	if lines := page.Weeks[0].Days[0].Cells[1].Lines; len(lines) != 0 {
+		t.Errorf("expected Alice to have no chores on the 5th of January, got %+v", lines)
+	}
+}
+
+func TestChoreTurnsBefore(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)
+	for _, c := range []Chore{
+		{Start: start},
+		{Start: start, Every: 3},
+		{Start: start, Weekdays: []time.Weekday{time.Tuesday, time.Sunday}},
+		{Start: start, Every: 2, Weekdays: []time.Weekday{time.Monday, time.Friday}},
+		{Start: start, SkipRedDays: true},
+		{Start: start, Every: 4, SkipRedDays: true},
+	} {
+		// Count the turns one day at a time, over more than a year with Easter and the 17th of May
+		walked := 0
+		for d := start; d.Before(time.Date(2027, time.February, 1, 0, 0, 0, 0, time.UTC)); d = d.AddDate(0, 0, 1) {
+			if got := c.turnsBefore(cal, d); got != walked {
+				t.Fatalf("expected %d turns before %s for %+v, got %d", walked, d.Format("2006-01-02"), c, got)
+			}
+			if c.due(cal, d) {
+				walked++
+			}
+		}
+	}
+}
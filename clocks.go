@@ -0,0 +1,26 @@
+package kitchencalendar
+
+import "time"
+
+// ClockChange returns how much the clocks are moved on the given day in the given time zone,
+// like one hour forward when daylight saving time starts, or 0 if they are not moved.
+// The changes come from the time zone database, so this works for any region.
+func ClockChange(date time.Time, loc *time.Location) time.Duration {
+	if loc == nil {
+		loc = time.Local
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+	end := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, loc)
+	_, before := start.Zone()
+	_, after := end.Zone()
+	return time.Duration(after-before) * time.Second
+}
+
+// addClockChanges adds a note to the days of the given week when the clocks are moved
+func addClockChanges(w *Week, loc *time.Location) {
+	for i := range w.Days {
+		if change := ClockChange(w.Days[i].Time, loc); change != 0 {
+			w.Days[i].Notes = append(w.Days[i].Notes, FormatClockChange(change))
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package kitchencalendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestClockChange(t *testing.T) {
+	for _, c := range []struct {
+		zone     string
+		date     time.Time
+		expected time.Duration
+	}{
+		{"Europe/Oslo", time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC), time.Hour},
+		{"Europe/Oslo", time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC), -time.Hour},
+		{"Europe/Oslo", time.Date(2025, time.October, 25, 0, 0, 0, 0, time.UTC), 0},
+		{"America/New_York", time.Date(2025, time.November, 2, 0, 0, 0, 0, time.UTC), -time.Hour},
+		{"America/New_York", time.Date(2025, time.October, 26, 0, 0, 0, 0, time.UTC), 0},
+		{"Australia/Sydney", time.Date(2025, time.October, 5, 0, 0, 0, 0, time.UTC), time.Hour},
+		{"Australia/Lord_Howe", time.Date(2025, time.April, 6, 0, 0, 0, 0, time.UTC), -30 * time.Minute},
+		{"Asia/Tokyo", time.Date(2025, time.March, 30, 0, 0, 0, 0, time.UTC), 0},
+	} {
+		loc, err := time.LoadLocation(c.zone)
+		if err != nil {
+			t.Skip(err)
+		}
+		if change := ClockChange(c.date, loc); change != c.expected {
+			t.Errorf("expected the clocks to move %s in %s on %s, got %s", c.expected, c.zone, c.date.Format("2006-01-02"), change)
+		}
+	}
+}
+
+func TestAddClockChanges(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip(err)
+	}
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 13, Names: []string{"Bob"}, ClockChanges: true, TimeZone: loc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notes := page.Weeks[0].Days[6].Notes; len(notes) != 1 || notes[0] != FormatClockChange(time.Hour) {
+		t.Errorf("expected the clocks to move forward on the 30th of March, got %v", notes)
+	}
+	for _, day := range page.Weeks[0].Days[:6] {
+		if len(day.Notes) != 0 {
+			t.Errorf("expected no notes on %s, got %v", day.Date, day.Notes)
+		}
+	}
+}
@@ -5,16 +5,53 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	kc "github.com/xyproto/kitchencalendar"
 )
 
 func main() {
-	outputFilename := flag.String("o", "", "an output PDF filename")
+	outputFilename := flag.String("o", "", "an output filename")
+	formatFlag := flag.String("format", "pdf", "the output format: pdf, svg, png, html, text or json")
+	dpiFlag := flag.Float64("dpi", 150, "the resolution of PNG images")
+	widthFlag := flag.Int("width", 0, "the width of PNG images in pixels, derived from the DPI if 0, or the width of text in columns")
+	heightFlag := flag.Int("height", 0, "the height of PNG images in pixels, derived from the DPI if 0")
+	grayscaleFlag := flag.Bool("grayscale", false, "render PNG images in grayscale")
+	ditherFlag := flag.Bool("dither", false, "render PNG images in 1-bit black and white, with dithering")
 	yearFlag := flag.Int("year", kc.GetCurrentYear(), "the year")
 	weekFlag := flag.Int("week", kc.GetCurrentWeek(), "the week number")
+	fromFlag := flag.String("from", "", "the first date (YYYY-MM-DD) of a multi-page PDF, instead of -year and -week")
+	toFlag := flag.String("to", "", "the last date (YYYY-MM-DD) of a multi-page PDF")
+	weeksFlag := flag.Int("weeks", 0, "the number of weeks in a multi-page PDF, starting with the given week")
 	nameString := flag.String("names", "Bob,Alice,Mallory,Judy", "names used in the calendar")
 	drawingFlag := flag.Bool("drawing", true, "include a drawing for each year and week in the top right corner")
+	templateFilename := flag.String("template", "", "a JSON page template to use instead of the built-in one")
+	eventsFlag := flag.String("events", "", "comma separated .ics files with events to show in the rows of the people")
+	csvFlag := flag.String("csv", "", "comma separated CSV files with events, like plans from a spreadsheet, with a date, a title and optionally a time and a person on each row")
+	csvColumnsFlag := flag.String("csvcolumns", "", "the columns of the CSV files, as names in the header row or numbers, like date=Dato,time=Tid,person=Hvem,title=Hva (default date=date,time=time,person=person,title=title)")
+	todoFlag := flag.String("todo", "", "comma separated todo.txt files, with tasks that are shown on their due:YYYY-MM-DD date")
+	recurringFilename := flag.String("recurring", "", "a file with household events, like waste collection, that repeat by RRULE")
+	sharedRowFlag := flag.String("sharedrow", "", "the name of an extra row, for the events that are not shown in any of the rows of the people")
+	eventRulesFlag := flag.String("eventrules", "", "comma separated rules for which rows the events are shown in, like calendar:Football=Alice,category:Work=Bob,attendee:bob@example.com=Bob")
+	moonFlag := flag.Bool("moon", false, "draw the phases of the moon in the day headers")
+	sunFlag := flag.String("sun", "none", "show the sunrise and sunset below the day labels: none, times or daylength")
+	latitudeFlag := flag.Float64("lat", 59.91, "the latitude used for the sunrise and sunset, in degrees north")
+	longitudeFlag := flag.Float64("lon", 10.75, "the longitude used for the sunrise and sunset, in degrees east")
+	timeZoneFlag := flag.String("timezone", "", "the time zone of the events, the moon phases, the sunrise and sunset and the clock changes, like Europe/Oslo (the default is the local time zone)")
+	nameDaysFlag := flag.String("namedays", "", "show name days below the day names, from a built-in table like nb_NO, or from a file with one \"MM-DD name, name\" on each line")
+	countdownsFilename := flag.String("countdowns", "", "a file with days to count down to, one \"MM-DD name\", \"YYYY-MM-DD name\" or \"easter+N name\" on each line")
+	countdownModeFlag := flag.String("countdownmode", "first", "show the countdowns on the first day of each page or on every day: first or daily")
+	countdownDaysFlag := flag.Int("countdowndays", 31, "how many days before a day the countdown starts")
+	holidaysFlag := flag.Bool("holidaynotes", false, "show the names of the public holidays below the day names")
+	clocksFlag := flag.Bool("clocks", false, "mark the days when the clocks are moved to or from daylight saving time")
+	periodsFilename := flag.String("periods", "", "a file with named ranges of days to shade, one \"YYYY-MM-DD YYYY-MM-DD name\" on each line")
+	birthdaysFilename := flag.String("birthdays", "", "a file with birthdays and anniversaries, one \"YYYY-MM-DD name\" or \"MM-DD name\" on each line")
+	leapDayFlag := flag.String("leapday", "feb28", "the day that birthdays on the 29th of February are shown in other years: feb28 or mar1")
+	custodyFilename := flag.String("custody", "", "a file with repeating patterns of who is responsible, like \"2025-01-06 Alice 7, Bob 7\" for alternating weeks")
+	shiftsFilename := flag.String("shifts", "", "a file with repeating shift patterns, like \"2025-01-06 Alice: D D N N off off off off\", and lines like \"N = Night 19-07\" with the meaning of the codes")
+	choresFilename := flag.String("chores", "", "a JSON file with chores that the people take turns doing")
+	trackersFilename := flag.String("trackers", "", "a JSON file with rows of boxes to tick off each day, like for vitamins or medicine")
+	icsFilename := flag.String("ics", "", "also write the red days and notable days of the calendar period to this .ics file")
 	verbose := flag.Bool("V", true, "verbose output")
 
 	flag.Parse()
@@ -23,24 +60,259 @@ func main() {
 	week := *weekFlag
 	names := strings.Split(*nameString, ",")
 
+	// A date range is given with -from and -to, or with -weeks
+	multiPage := *fromFlag != "" || *toFlag != "" || *weeksFlag > 0
+	var from, to time.Time
+	if multiPage {
+		if *formatFlag != "pdf" {
+			fmt.Fprintln(os.Stderr, "-from, -to and -weeks can only be used with -format pdf")
+			return
+		}
+		from = kc.FirstMondayOfWeek(year, week)
+		if *fromFlag != "" {
+			t, err := time.Parse("2006-01-02", *fromFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "invalid -from date:", err)
+				return
+			}
+			from = t
+		}
+		switch {
+		case *toFlag != "":
+			t, err := time.Parse("2006-01-02", *toFlag)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "invalid -to date:", err)
+				return
+			}
+			to = t
+		case *weeksFlag > 0:
+			to = kc.FirstMondayOfWeek(from.ISOWeek()).AddDate(0, 0, 7*(*weeksFlag)-1)
+		default:
+			fmt.Fprintln(os.Stderr, "-from must be combined with -to or -weeks")
+			return
+		}
+	}
+
 	filename := ""
-	if *outputFilename == "" {
-		filename = fmt.Sprintf("calendar_w%d_%d.pdf", week, year)
-	} else {
+	switch {
+	case *outputFilename != "":
 		filename = *outputFilename
+	case multiPage:
+		filename = fmt.Sprintf("calendar_%s_%s.pdf", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	default:
+		filename = fmt.Sprintf("calendar_w%d_%d.%s", week, year, *formatFlag)
 	}
 
-	pdfBytes, err := kc.GeneratePDF(year, week, names, *drawingFlag)
+	opts := kc.Options{Year: year, Week: week, Names: names, Drawing: *drawingFlag}
+	if *templateFilename != "" {
+		tmpl, err := kc.LoadTemplate(*templateFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Template = tmpl
+	}
+	if *eventsFlag != "" {
+		for _, eventsFilename := range strings.Split(*eventsFlag, ",") {
+			events, err := kc.LoadICS(eventsFilename)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			opts.Events = append(opts.Events, events...)
+		}
+	}
+	if *csvFlag != "" {
+		columns, err := kc.ParseCSVColumns(*csvColumnsFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		for _, csvFilename := range strings.Split(*csvFlag, ",") {
+			events, err := kc.LoadCSVEvents(csvFilename, columns)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			opts.Events = append(opts.Events, events...)
+		}
+	}
+	if *todoFlag != "" {
+		for _, todoFilename := range strings.Split(*todoFlag, ",") {
+			events, err := kc.LoadTodoTxt(todoFilename)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+			opts.Events = append(opts.Events, events...)
+		}
+	}
+	if *recurringFilename != "" {
+		events, err := kc.LoadRecurringEvents(*recurringFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Events = append(opts.Events, events...)
+	}
+	opts.SharedRow = strings.TrimSpace(*sharedRowFlag)
+	opts.MoonPhases = *moonFlag
+	opts.ClockChanges = *clocksFlag
+	opts.HolidayNotes = *holidaysFlag
+	if *countdownsFilename != "" {
+		countdowns, err := kc.LoadCountdowns(*countdownsFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Countdowns = countdowns
+	}
+	countdownMode, err := kc.ParseCountdownMode(*countdownModeFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	opts.CountdownMode, opts.CountdownDays = countdownMode, *countdownDaysFlag
+	if *nameDaysFlag != "" {
+		nameDays, err := kc.BuiltinNameDays(*nameDaysFlag)
+		if err != nil {
+			nameDays, err = kc.LoadNameDays(*nameDaysFlag)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.NameDays = nameDays
+	}
+	sun, err := kc.ParseSunDisplay(*sunFlag)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
 	}
+	opts.Sun = sun
+	opts.Latitude, opts.Longitude = *latitudeFlag, *longitudeFlag
+	if *timeZoneFlag != "" {
+		loc, err := time.LoadLocation(*timeZoneFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid -timezone:", err)
+			return
+		}
+		opts.TimeZone = loc
+	}
+	if *periodsFilename != "" {
+		periods, err := kc.LoadPeriods(*periodsFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Periods = periods
+	}
+	if *birthdaysFilename != "" {
+		birthdays, err := kc.LoadBirthdays(*birthdaysFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Birthdays = birthdays
+	}
+	leapDay, err := kc.ParseLeapDayPolicy(*leapDayFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	opts.LeapDay = leapDay
+	if *custodyFilename != "" {
+		patterns, err := kc.LoadCustodyPatterns(*custodyFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Custody = patterns
+	}
+	if *shiftsFilename != "" {
+		patterns, err := kc.LoadShiftPatterns(*shiftsFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Shifts = patterns
+	}
+	if *choresFilename != "" {
+		chores, err := kc.LoadChores(*choresFilename, names)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Chores = chores
+	}
+	if *trackersFilename != "" {
+		trackers, err := kc.LoadTrackers(*trackersFilename)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.Trackers = trackers
+	}
+	if *eventRulesFlag != "" {
+		rules, err := kc.ParseEventRules(*eventRulesFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		opts.EventRules = rules
+	}
+
+	var data []byte
+	switch {
+	case multiPage:
+		data, err = kc.GenerateRangePDF(opts, from, to)
+	case *formatFlag == "pdf":
+		data, err = kc.GeneratePDFWithOptions(opts)
+	case *formatFlag == "svg":
+		data, err = kc.GenerateSVGWithOptions(opts)
+	case *formatFlag == "json":
+		data, err = kc.GenerateJSON(opts)
+	case *formatFlag == "html":
+		data, err = kc.GenerateHTMLWithOptions(opts)
+	case *formatFlag == "png":
+		ropts := kc.RasterOptions{
+			Width:     *widthFlag,
+			Height:    *heightFlag,
+			DPI:       *dpiFlag,
+			Grayscale: *grayscaleFlag,
+			Dither:    *ditherFlag,
+		}
+		data, err = kc.GeneratePNG(opts, ropts)
+	case *formatFlag == "text":
+		columns := *widthFlag
+		if columns == 0 {
+			columns = terminalWidth()
+		}
+		if columns == 0 {
+			columns = 80
+		}
+		// Only use colors when writing directly to a terminal
+		useColor := *outputFilename == "" && terminalWidth() > 0 && os.Getenv("NO_COLOR") == ""
+		data, err = kc.GenerateText(opts, columns, useColor)
+	default:
+		err = fmt.Errorf("unsupported format: %s", *formatFlag)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+
+	// Text is written to stdout, unless an output filename is given
+	if *formatFlag == "text" && *outputFilename == "" {
+		os.Stdout.Write(data)
+		return
+	}
 
 	if *verbose {
 		fmt.Printf("Writing %s... ", filename)
 	}
 
-	if err := os.WriteFile(filename, pdfBytes, 0644); err != nil {
+	if err := os.WriteFile(filename, data, 0644); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		return
 	}
@@ -49,4 +321,30 @@ func main() {
 		fmt.Println("done")
 	}
 
+	if *icsFilename != "" {
+		// The red days and notable days that are shown on the generated pages
+		pages := []kc.Options{opts}
+		if multiPage {
+			var err error
+			if pages, err = kc.PagesForRange(opts, from, to); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return
+			}
+		}
+		icsBytes, err := kc.GeneratePagesICS(pages)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		if *verbose {
+			fmt.Printf("Writing %s... ", *icsFilename)
+		}
+		if err := os.WriteFile(*icsFilename, icsBytes, 0644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return
+		}
+		if *verbose {
+			fmt.Println("done")
+		}
+	}
 }
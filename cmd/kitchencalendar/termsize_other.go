@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd
+
+package main
+
+// terminalWidth returns 0, since the terminal size can not be detected on this platform
+func terminalWidth() int {
+	return 0
+}
@@ -5,11 +5,14 @@ package main
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/xyproto/env/v2"
@@ -17,16 +20,188 @@ import (
 )
 
 type CalendarRequest struct {
-	FromDate  string   `json:"fromDate"`
-	ToDate    string   `json:"toDate"`
-	Names     []string `json:"names"`
-	Drawing   bool     `json:"drawing"`
-	WeeksSpan int      `json:"weeksSpan"` // 1 or 2 weeks per PDF
+	FromDate      string   `json:"fromDate"`
+	ToDate        string   `json:"toDate"`
+	Names         []string `json:"names"`
+	Drawing       bool     `json:"drawing"`
+	MoonPhases    bool     `json:"moonPhases"`    // draw the phases of the moon in the day headers
+	WeeksSpan     int      `json:"weeksSpan"`     // 1 or 2 weeks per PDF
+	ICS           bool     `json:"ics"`           // also add the red days and notable days as an .ics file
+	SinglePDF     bool     `json:"singlePDF"`     // one PDF with all pages, instead of one PDF per page
+	Events        string   `json:"events"`        // the contents of an .ics file with events to show in the rows
+	EventRules    string   `json:"eventRules"`    // which rows the events are shown in, like calendar:Football=Alice
+	CSV           string   `json:"csv"`           // the contents of a CSV file with events, like plans from a spreadsheet
+	CSVColumns    string   `json:"csvColumns"`    // the columns of the CSV file, like date=Dato,time=Tid,person=Hvem,title=Hva
+	Todo          string   `json:"todo"`          // tasks in the todo.txt format, shown on their due dates
+	Recurring     string   `json:"recurring"`     // household events that repeat, see kc.ParseRecurringEvents
+	SharedRow     string   `json:"sharedRow"`     // the name of an extra row, for the events that are not shown in any other row
+	Chores        string   `json:"chores"`        // a JSON list of chores that the people take turns doing
+	Trackers      string   `json:"trackers"`      // a JSON list of rows with boxes to tick off each day, see kc.ParseTrackers
+	Custody       string   `json:"custody"`       // repeating patterns of who is responsible, one "YYYY-MM-DD name days, name days" on each line
+	Shifts        string   `json:"shifts"`        // repeating shift patterns, one "YYYY-MM-DD name: codes" or "code = meaning" on each line
+	Periods       string   `json:"periods"`       // named ranges of days to shade, one "YYYY-MM-DD YYYY-MM-DD name" on each line
+	Birthdays     string   `json:"birthdays"`     // birthdays and anniversaries, one "YYYY-MM-DD name" on each line
+	LeapDay       string   `json:"leapDay"`       // feb28 or mar1, for birthdays on the 29th of February
+	Sun           string   `json:"sun"`           // none, times or daylength, shown below the day labels
+	Latitude      float64  `json:"latitude"`      // the location used for the sunrise and sunset, in degrees north
+	Longitude     float64  `json:"longitude"`     // the location used for the sunrise and sunset, in degrees east
+	TimeZone      string   `json:"timeZone"`      // the time zone of the events, the moon phases, the sunrise and sunset and the clock changes, like Europe/Oslo
+	ClockChanges  bool     `json:"clockChanges"`  // mark the days when the clocks are moved to or from daylight saving time
+	HolidayNotes  bool     `json:"holidayNotes"`  // show the names of the public holidays below the day names
+	NameDays      string   `json:"nameDays"`      // the built-in name days to show below the day labels, like nb_NO
+	Countdowns    string   `json:"countdowns"`    // days to count down to, one "MM-DD name" on each line, see kc.ParseCountdowns
+	CountdownMode string   `json:"countdownMode"` // first or daily
+	CountdownDays int      `json:"countdownDays"` // how many days before a day the countdown starts
+}
+
+// options returns the settings for generating the calendars, with the events of the request
+func (req CalendarRequest) options() (kc.Options, error) {
+	opts := kc.Options{Names: req.Names, Drawing: req.Drawing, MoonPhases: req.MoonPhases, ClockChanges: req.ClockChanges, HolidayNotes: req.HolidayNotes, SharedRow: strings.TrimSpace(req.SharedRow)}
+	if req.Events != "" {
+		events, err := kc.ParseICS(strings.NewReader(req.Events))
+		if err != nil {
+			return opts, fmt.Errorf("invalid events: %v", err)
+		}
+		opts.Events = events
+	}
+	if req.CSV != "" {
+		columns, err := kc.ParseCSVColumns(req.CSVColumns)
+		if err != nil {
+			return opts, err
+		}
+		events, err := kc.ParseCSVEvents(strings.NewReader(req.CSV), columns)
+		if err != nil {
+			return opts, fmt.Errorf("invalid CSV events: %v", err)
+		}
+		opts.Events = append(opts.Events, events...)
+	}
+	if req.Todo != "" {
+		events, err := kc.ParseTodoTxt(strings.NewReader(req.Todo))
+		if err != nil {
+			return opts, fmt.Errorf("invalid todo.txt tasks: %v", err)
+		}
+		opts.Events = append(opts.Events, events...)
+	}
+	if req.Recurring != "" {
+		events, err := kc.ParseRecurringEvents(strings.NewReader(req.Recurring))
+		if err != nil {
+			return opts, fmt.Errorf("invalid recurring events: %v", err)
+		}
+		opts.Events = append(opts.Events, events...)
+	}
+	if req.EventRules != "" {
+		rules, err := kc.ParseEventRules(req.EventRules)
+		if err != nil {
+			return opts, err
+		}
+		opts.EventRules = rules
+	}
+	if req.Chores != "" {
+		chores, err := kc.ParseChores([]byte(req.Chores), req.Names)
+		if err != nil {
+			return opts, fmt.Errorf("invalid chores: %v", err)
+		}
+		opts.Chores = chores
+	}
+	if req.Trackers != "" {
+		trackers, err := kc.ParseTrackers([]byte(req.Trackers))
+		if err != nil {
+			return opts, fmt.Errorf("invalid trackers: %v", err)
+		}
+		opts.Trackers = trackers
+	}
+	if req.Custody != "" {
+		patterns, err := kc.ParseCustodyPatterns(strings.NewReader(req.Custody))
+		if err != nil {
+			return opts, fmt.Errorf("invalid custody patterns: %v", err)
+		}
+		opts.Custody = patterns
+	}
+	if req.Shifts != "" {
+		patterns, err := kc.ParseShiftPatterns(strings.NewReader(req.Shifts))
+		if err != nil {
+			return opts, fmt.Errorf("invalid shift patterns: %v", err)
+		}
+		opts.Shifts = patterns
+	}
+	if req.Periods != "" {
+		periods, err := kc.ParsePeriods(strings.NewReader(req.Periods))
+		if err != nil {
+			return opts, fmt.Errorf("invalid periods: %v", err)
+		}
+		opts.Periods = periods
+	}
+	if req.Birthdays != "" {
+		birthdays, err := kc.ParseBirthdays(strings.NewReader(req.Birthdays))
+		if err != nil {
+			return opts, fmt.Errorf("invalid birthdays: %v", err)
+		}
+		opts.Birthdays = birthdays
+	}
+	leapDay, err := kc.ParseLeapDayPolicy(req.LeapDay)
+	if err != nil {
+		return opts, err
+	}
+	opts.LeapDay = leapDay
+	if err := setSunOptions(&opts, req.Sun, req.Latitude, req.Longitude); err != nil {
+		return opts, err
+	}
+	if err := setTimeZone(&opts, req.TimeZone); err != nil {
+		return opts, err
+	}
+	if req.NameDays != "" {
+		if opts.NameDays, err = kc.BuiltinNameDays(req.NameDays); err != nil {
+			return opts, err
+		}
+	}
+	if req.Countdowns != "" {
+		countdowns, err := kc.ParseCountdowns(strings.NewReader(req.Countdowns))
+		if err != nil {
+			return opts, fmt.Errorf("invalid countdowns: %v", err)
+		}
+		opts.Countdowns = countdowns
+	}
+	if opts.CountdownMode, err = kc.ParseCountdownMode(req.CountdownMode); err != nil {
+		return opts, err
+	}
+	opts.CountdownDays = req.CountdownDays
+	return opts, nil
+}
+
+// setSunOptions sets how the sunrise and sunset are shown, and for which location
+func setSunOptions(opts *kc.Options, sun string, latitude, longitude float64) error {
+	display, err := kc.ParseSunDisplay(sun)
+	if err != nil {
+		return err
+	}
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("invalid location: %g, %g", latitude, longitude)
+	}
+	opts.Sun, opts.Latitude, opts.Longitude = display, latitude, longitude
+	return nil
+}
+
+// setTimeZone sets the time zone of the events, the moon phases, the sunrise and sunset and the clock changes, like Europe/Oslo.
+// The local time zone of the server is used if timeZone is empty.
+func setTimeZone(opts *kc.Options, timeZone string) error {
+	if timeZone == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return fmt.Errorf("invalid time zone: %v", err)
+	}
+	opts.TimeZone = loc
+	return nil
 }
 
 const (
 	daysPerWeek      = 7
 	defaultWeeksSpan = 2
+	maxImageSize     = 8000     // the largest accepted image width or height, in pixels
+	maxImagePixels   = 16 << 20 // the largest accepted image width times height, about 64 MiB when drawing in color
+	maxDPI           = 600
+	maxRequestSize   = 4 << 20 // the largest accepted request body, with the .ics and CSV files, in bytes
 )
 
 var verboseLogging = env.Bool("VERBOSE")
@@ -37,55 +212,116 @@ func logVerbose(message string) {
 	}
 }
 
-func generateCalendars(req CalendarRequest, fromDate, toDate time.Time) ([]byte, error) {
+// generateCalendars writes a zip file with the calendars for the given dates to the given writer.
+// Each file is sent as soon as it has been generated, instead of collecting the whole zip file in memory.
+func generateCalendars(w io.Writer, req CalendarRequest, opts kc.Options, fromDate, toDate time.Time) error {
 	if req.WeeksSpan <= 0 {
 		req.WeeksSpan = defaultWeeksSpan // Default to defaultWeeksSpan if WeeksSpan is not specified or invalid
 	}
 
-	buffer := new(bytes.Buffer)
-	zipWriter := zip.NewWriter(buffer)
+	zipWriter := zip.NewWriter(w)
 
-	start := fromDate
-	for firstIteration := true; firstIteration || start.Before(toDate); firstIteration = false {
-		expectedStart := start
-		end := start.AddDate(0, 0, daysPerWeek*req.WeeksSpan-1)
-		if end.After(toDate) {
-			end = toDate
-		}
-
-		year := start.Year()
-		week := kc.GetWeekForDate(start)
-		logVerbose(fmt.Sprintf("Generating PDF for weeks %d-%d of year %d", week, week+req.WeeksSpan-1, year))
+	// The pages of the generated PDF files, for the ICS file
+	var pages []kc.Options
 
-		pdfBytes, err := kc.GeneratePDF(year, week, req.Names, req.Drawing)
+	if req.SinglePDF {
+		logVerbose(fmt.Sprintf("Generating a single PDF for %s to %s", fromDate.Format("2006-01-02"), toDate.Format("2006-01-02")))
+		fw, err := zipWriter.Create("calendars.pdf")
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate PDF: %v", err)
+			return fmt.Errorf("failed to create zip entry: %v", err)
 		}
-
-		fileName := fmt.Sprintf("calendar_%d-%d.pdf", year, week)
-		fw, err := zipWriter.Create(fileName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create zip entry: %v", err)
+		if err := kc.WriteRangePDF(fw, opts, fromDate, toDate); err != nil {
+			return fmt.Errorf("failed to generate PDF: %v", err)
+		}
+		if err := flushZip(zipWriter, w); err != nil {
+			return err
 		}
-		if _, err := fw.Write(pdfBytes); err != nil {
-			return nil, fmt.Errorf("failed to write to zip: %v", err)
+		if pages, err = kc.PagesForRange(opts, fromDate, toDate); err != nil {
+			return fmt.Errorf("failed to find the pages of the PDF: %v", err)
 		}
+	} else {
+		start := fromDate
+		for firstIteration := true; firstIteration || start.Before(toDate); firstIteration = false {
+			expectedStart := start
+			end := start.AddDate(0, 0, daysPerWeek*req.WeeksSpan-1)
+			if end.After(toDate) {
+				end = toDate
+			}
+
+			year := start.Year()
+			week := kc.GetWeekForDate(start)
+			logVerbose(fmt.Sprintf("Generating PDF for weeks %d-%d of year %d", week, week+req.WeeksSpan-1, year))
 
-		// Move to the next set of weeks, avoiding overlap
-		start = start.AddDate(0, 0, daysPerWeek*req.WeeksSpan)
+			fileName := fmt.Sprintf("calendar_%d-%d.pdf", year, week)
+			fw, err := zipWriter.Create(fileName)
+			if err != nil {
+				return fmt.Errorf("failed to create zip entry: %v", err)
+			}
+			opts.Year, opts.Week = year, week
+			if err := kc.WritePDF(fw, opts); err != nil {
+				return fmt.Errorf("failed to generate PDF: %v", err)
+			}
+			pages = append(pages, opts)
+			if err := flushZip(zipWriter, w); err != nil {
+				return err
+			}
+
+			// Move to the next set of weeks, avoiding overlap
+			start = start.AddDate(0, 0, daysPerWeek*req.WeeksSpan)
+
+			// Check if the iteration is progressing as expected
+			if start.Before(expectedStart.AddDate(0, 0, daysPerWeek*req.WeeksSpan)) {
+				fmt.Fprintf(os.Stderr, "Warning: Iteration did not progress as expected. Current start: %v, Expected start: %v\n", start, expectedStart.AddDate(0, 0, daysPerWeek*req.WeeksSpan))
+			}
+		}
+	}
 
-		// Check if the iteration is progressing as expected
-		if start.Before(expectedStart.AddDate(0, 0, daysPerWeek*req.WeeksSpan)) {
-			fmt.Fprintf(os.Stderr, "Warning: Iteration did not progress as expected. Current start: %v, Expected start: %v\n", start, expectedStart.AddDate(0, 0, daysPerWeek*req.WeeksSpan))
+	if req.ICS {
+		logVerbose(fmt.Sprintf("Generating ICS file for %d pages", len(pages)))
+		fw, err := zipWriter.Create("calendar.ics")
+		if err != nil {
+			return fmt.Errorf("failed to create zip entry: %v", err)
+		}
+		if err := kc.WritePagesICS(fw, pages); err != nil {
+			return fmt.Errorf("failed to generate ICS file: %v", err)
 		}
 	}
 
 	if err := zipWriter.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close zip writer: %v", err)
+		return fmt.Errorf("failed to close zip writer: %v", err)
 	}
 
 	logVerbose("PDF generation and zipping completed successfully")
-	return buffer.Bytes(), nil
+	return nil
+}
+
+// flushZip sends the zip entries that have been written so far, if the writer can be flushed
+func flushZip(zipWriter *zip.Writer, w io.Writer) error {
+	if err := zipWriter.Flush(); err != nil {
+		return fmt.Errorf("failed to write to zip: %v", err)
+	}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// trackingWriter is a http.ResponseWriter that remembers if anything has been written,
+// since an error can only be sent with http.Error before the response has started
+type trackingWriter struct {
+	http.ResponseWriter
+	written bool
+}
+
+func (tw *trackingWriter) Write(p []byte) (int, error) {
+	tw.written = true
+	return tw.ResponseWriter.Write(p)
+}
+
+func (tw *trackingWriter) Flush() {
+	if f, ok := tw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
 }
 
 func handleCreateCalendar(w http.ResponseWriter, r *http.Request) {
@@ -98,6 +334,7 @@ func handleCreateCalendar(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req CalendarRequest
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, "Invalid request", http.StatusBadRequest)
 		logVerbose(fmt.Sprintf("Error decoding request: %v", err))
@@ -119,18 +356,322 @@ func handleCreateCalendar(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	zipData, err := generateCalendars(req, fromDate, toDate)
+	if len(req.Names) == 0 {
+		http.Error(w, "No names given", http.StatusBadRequest)
+		logVerbose("Error: no names given")
+		return
+	}
+
+	opts, err := req.options()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error in request: %v", err))
+		return
+	}
+
+	tw := &trackingWriter{ResponseWriter: w}
+
+	// A single PDF without an .ics file is sent as it is, not in a zip file
+	if req.SinglePDF && !req.ICS {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", "attachment; filename=calendars.pdf")
+		err = kc.WriteRangePDF(tw, opts, fromDate, toDate)
+	} else {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", "attachment; filename=calendars.zip")
+		err = generateCalendars(tw, req, opts, fromDate, toDate)
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
 		logVerbose(fmt.Sprintf("Error generating calendars: %v", err))
+		if !tw.written {
+			w.Header().Del("Content-Disposition")
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// optionsFromQuery returns calendar options from the "year", "week", "names", "drawing", "moon",
+// "holidays", "clocks", "namedays", "sun", "lat", "lon" and "tz" URL query parameters.
+// The current ISO year and week in the given time zone are used if they are not given.
+func optionsFromQuery(r *http.Request) (kc.Options, error) {
+	q := r.URL.Query()
+	opts := kc.Options{
+		Names:        []string{"Bob", "Alice", "Mallory", "Judy"},
+		Drawing:      q.Get("drawing") != "false",
+		MoonPhases:   q.Get("moon") == "true",
+		ClockChanges: q.Get("clocks") == "true",
+		HolidayNotes: q.Get("holidays") == "true",
+	}
+	if err := setTimeZone(&opts, q.Get("tz")); err != nil {
+		return opts, err
+	}
+	opts.Year, opts.Week = startOfToday(opts.TimeZone).ISOWeek()
+	var err error
+	if s := q.Get("year"); s != "" {
+		if opts.Year, err = strconv.Atoi(s); err != nil {
+			return opts, fmt.Errorf("invalid year: %v", err)
+		}
+	}
+	if s := q.Get("week"); s != "" {
+		if opts.Week, err = strconv.Atoi(s); err != nil || opts.Week < 1 || opts.Week > 53 {
+			return opts, fmt.Errorf("invalid week: %s", s)
+		}
+	}
+	if s := q.Get("date"); s != "" {
+		date, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			return opts, fmt.Errorf("invalid date: %v", err)
+		}
+		opts.Year, opts.Week = date.ISOWeek()
+	}
+	if s := q.Get("names"); s != "" {
+		opts.Names = strings.Split(s, ",")
+		for i, name := range opts.Names {
+			opts.Names[i] = strings.TrimSpace(name)
+		}
+	}
+	if s := q.Get("sun"); s != "" {
+		var latitude, longitude float64
+		if latitude, err = strconv.ParseFloat(q.Get("lat"), 64); err != nil {
+			return opts, fmt.Errorf("invalid latitude: %s", q.Get("lat"))
+		}
+		if longitude, err = strconv.ParseFloat(q.Get("lon"), 64); err != nil {
+			return opts, fmt.Errorf("invalid longitude: %s", q.Get("lon"))
+		}
+		if err := setSunOptions(&opts, s, latitude, longitude); err != nil {
+			return opts, err
+		}
+	}
+	if s := q.Get("namedays"); s != "" {
+		if opts.NameDays, err = kc.BuiltinNameDays(s); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+func handleSVG(w http.ResponseWriter, r *http.Request) {
+	logVerbose("Received request for an SVG calendar")
+
+	opts, err := optionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/zip")
-	w.Header().Set("Content-Disposition", "attachment; filename=calendars.zip")
-	if _, err := io.Copy(w, bytes.NewReader(zipData)); err != nil {
-		logVerbose(fmt.Sprintf("Error sending zip file: %v", err))
+	svgData, err := kc.GenerateSVGWithOptions(opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logVerbose(fmt.Sprintf("Error generating SVG: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+	if _, err := w.Write(svgData); err != nil {
+		logVerbose(fmt.Sprintf("Error sending SVG: %v", err))
+	}
+}
+
+func handleHTML(w http.ResponseWriter, r *http.Request) {
+	logVerbose("Received request for an HTML calendar")
+
+	opts, err := optionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
+		return
+	}
+
+	htmlData, err := kc.GenerateHTMLWithOptions(opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logVerbose(fmt.Sprintf("Error generating HTML: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if _, err := w.Write(htmlData); err != nil {
+		logVerbose(fmt.Sprintf("Error sending HTML: %v", err))
+	}
+}
+
+// rasterOptionsFromQuery returns image options from the "width", "height", "dpi", "grayscale" and "dither" URL query parameters
+func rasterOptionsFromQuery(r *http.Request) (kc.RasterOptions, error) {
+	q := r.URL.Query()
+	ropts := kc.RasterOptions{
+		Grayscale: q.Get("grayscale") == "true",
+		Dither:    q.Get("dither") == "true",
+	}
+	var err error
+	if s := q.Get("width"); s != "" {
+		if ropts.Width, err = strconv.Atoi(s); err != nil || ropts.Width < 1 || ropts.Width > maxImageSize {
+			return ropts, fmt.Errorf("invalid width: %s", s)
+		}
+	}
+	if s := q.Get("height"); s != "" {
+		if ropts.Height, err = strconv.Atoi(s); err != nil || ropts.Height < 1 || ropts.Height > maxImageSize {
+			return ropts, fmt.Errorf("invalid height: %s", s)
+		}
+	}
+	if s := q.Get("dpi"); s != "" {
+		if ropts.DPI, err = strconv.ParseFloat(s, 64); err != nil || ropts.DPI < 1 || ropts.DPI > maxDPI {
+			return ropts, fmt.Errorf("invalid dpi: %s", s)
+		}
+	}
+	return ropts, nil
+}
+
+// checkImageSize checks that the image is not too large to draw, also when the width or height
+// is derived from the other one or from the DPI
+func checkImageSize(opts kc.Options, ropts kc.RasterOptions) error {
+	width, height, err := kc.ImageSize(opts, ropts)
+	if err != nil {
+		return err
+	}
+	if width > maxImageSize || height > maxImageSize || width*height > maxImagePixels {
+		return fmt.Errorf("the image is too large: %dx%d pixels, the largest accepted size is %d pixels in total", width, height, maxImagePixels)
+	}
+	return nil
+}
+
+func handleJSON(w http.ResponseWriter, r *http.Request) {
+	logVerbose("Received request for a JSON calendar")
+
+	opts, err := optionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
+		return
+	}
+
+	jsonData, err := kc.GenerateJSON(opts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logVerbose(fmt.Sprintf("Error generating JSON: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(jsonData); err != nil {
+		logVerbose(fmt.Sprintf("Error sending JSON: %v", err))
+	}
+}
+
+func handlePNG(w http.ResponseWriter, r *http.Request) {
+	logVerbose("Received request for a PNG calendar")
+
+	opts, err := optionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
+		return
+	}
+
+	ropts, err := rasterOptionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
+		return
+	}
+
+	if err := checkImageSize(opts, ropts); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error: %v", err))
+		return
+	}
+
+	pngData, err := kc.GeneratePNG(opts, ropts)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logVerbose(fmt.Sprintf("Error generating PNG: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	if _, err := w.Write(pngData); err != nil {
+		logVerbose(fmt.Sprintf("Error sending PNG: %v", err))
+	}
+}
+
+// handleDashboard serves the calendar for the current weeks as a PNG image, with the exact resolution and
+// shades of gray of a display device. The ETag and Last-Modified headers lets devices poll without downloading
+// the same image again.
+func handleDashboard(w http.ResponseWriter, r *http.Request) {
+	logVerbose("Received request for a dashboard image")
+
+	deviceName := r.URL.Query().Get("device")
+	profile, ok := kc.DeviceProfiles[deviceName]
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown device: %q, the known devices are: %s", deviceName, strings.Join(kc.DeviceProfileNames(), ", ")), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error: unknown device %q", deviceName))
+		return
+	}
+
+	rotate := 0
+	if s := r.URL.Query().Get("rotate"); s != "" {
+		var err error
+		if rotate, err = strconv.Atoi(s); err != nil || rotate%90 != 0 {
+			http.Error(w, "Invalid rotation, must be 0, 90, 180 or 270", http.StatusBadRequest)
+			logVerbose(fmt.Sprintf("Error: invalid rotation %q", s))
+			return
+		}
+	}
+
+	opts, err := optionsFromQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		logVerbose(fmt.Sprintf("Error parsing query: %v", err))
+		return
+	}
+
+	// The image is computed for today, since the notes below the day names, like the phases of the
+	// moon and the countdowns, can change from day to day. The ETag is derived from the inputs, so
+	// that devices that poll for a new image get an answer without the image being drawn. The page
+	// shows the weeks of the same day that the ETag is made for, unless other weeks are asked for.
+	today := startOfToday(opts.TimeZone)
+	if q := r.URL.Query(); q.Get("year") == "" && q.Get("week") == "" && q.Get("date") == "" {
+		opts.Year, opts.Week = today.ISOWeek()
+	}
+	// The ETag is weak, since the drawing is random and the same inputs can give other pixels
+	etag := fmt.Sprintf("W/\"%x\"", sha256.Sum256([]byte(fmt.Sprintf("%s|%+v|%d|%s|%s", deviceName, profile, rotate, r.URL.Query().Encode(), today.Format("2006-01-02")))))
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	pngData, err := kc.GenerateDevicePNG(profile, opts, rotate)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		logVerbose(fmt.Sprintf("Error generating dashboard image: %v", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	http.ServeContent(w, r, "dashboard.png", today, bytes.NewReader(pngData))
+}
+
+// startOfToday returns the start of the current day in the given time zone, or in the local time zone if loc is nil
+func startOfToday(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	now := time.Now().In(loc)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+}
+
+// etagMatches checks if the If-None-Match header of a request has the given ETag, with the weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, s := range strings.Split(ifNoneMatch, ",") {
+		if s = strings.TrimPrefix(strings.TrimSpace(s), "W/"); s == etag || s == "*" {
+			return true
+		}
 	}
+	return false
 }
 
 func main() {
@@ -143,6 +684,11 @@ func main() {
 	})
 
 	http.HandleFunc("/createcalendar", handleCreateCalendar)
+	http.HandleFunc("/calendar.svg", handleSVG)
+	http.HandleFunc("/calendar.png", handlePNG)
+	http.HandleFunc("/calendar.html", handleHTML)
+	http.HandleFunc("/calendar.json", handleJSON)
+	http.HandleFunc("/dashboard.png", handleDashboard)
 
 	fmt.Println("Serving on http://localhost:8080")
 	if err := http.ListenAndServe(":8080", nil); err != nil {
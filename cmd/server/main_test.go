@@ -0,0 +1,61 @@
+//go:build nb_NO || en_US
+
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOptionsFromQueryDate(t *testing.T) {
+	// ISO week years are not the same as calendar years around New Year
+	tests := []struct {
+		date       string
+		year, week int
+	}{
+		{"2024-12-30", 2025, 1},
+		{"2027-01-01", 2026, 53},
+		{"2025-05-17", 2025, 20},
+	}
+	for _, tt := range tests {
+		opts, err := optionsFromQuery(httptest.NewRequest("GET", "/calendar.json?date="+tt.date, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.Year != tt.year || opts.Week != tt.week {
+			t.Errorf("date=%s gave week %d of %d, want week %d of %d", tt.date, opts.Week, opts.Year, tt.week, tt.year)
+		}
+	}
+}
+
+func TestOptionsFromQueryCurrentWeek(t *testing.T) {
+	// The current week is the ISO week of today in the given time zone
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skip(err)
+	}
+	opts, err := optionsFromQuery(httptest.NewRequest("GET", "/calendar.json?tz=Pacific/Kiritimati", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if year, week := time.Now().In(loc).ISOWeek(); opts.Year != year || opts.Week != week {
+		t.Errorf("expected week %d of %d, got week %d of %d", week, year, opts.Week, opts.Year)
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	for header, expected := range map[string]bool{
+		`W/"abc"`:        true,
+		`"abc"`:          true,
+		`"xyz", W/"abc"`: true,
+		`*`:              true,
+		`"xyz"`:          false,
+		``:               false,
+	} {
+		if got := etagMatches(header, etag); got != expected {
+			t.Errorf("etagMatches(%q, %q) = %v, want %v", header, etag, got, expected)
+		}
+	}
+}
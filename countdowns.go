@@ -0,0 +1,157 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// Countdown is a day that is counted down to, like Christmas Eve or the start of the summer holiday
+type Countdown struct {
+	Name         string
+	Date         time.Time  // a single day, at midnight UTC, or zero for a day that comes every year
+	Month        time.Month // the month and day of a day that comes every year
+	Day          int
+	Easter       bool // the day is EasterOffset days after Easter Sunday, every year
+	EasterOffset int
+}
+
+// CountdownMode decides on which days the countdowns are shown
+type CountdownMode string
+
+const (
+	CountdownFirstDay CountdownMode = "first" // on the first day of each page, the default
+	CountdownEveryDay CountdownMode = "daily" // on every day
+)
+
+// defaultCountdownDays is the number of days before a target that the countdown starts, by default
+const defaultCountdownDays = 31
+
+// ParseCountdownMode parses "first" or "daily". An empty string gives the default mode.
+func ParseCountdownMode(s string) (CountdownMode, error) {
+	switch mode := CountdownMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "", CountdownFirstDay:
+		return CountdownFirstDay, nil
+	case CountdownEveryDay:
+		return mode, nil
+	}
+	return CountdownFirstDay, fmt.Errorf("unknown countdown mode, expected first or daily: %s", s)
+}
+
+// ParseCountdowns reads the days to count down to, one on each line, with the day first and then the name:
+//
+//	12-24 Christmas Eve
+//	2026-06-19 Summer holiday
+//	easter Easter
+//	easter+39 Ascension Day
+//
+// The day is either a single date, a month and day that comes every year, or a number of days
+// before or after Easter Sunday. Empty lines and lines starting with # are skipped.
+func ParseCountdowns(r io.Reader) ([]Countdown, error) {
+	var countdowns []Countdown
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		target, name, _ := strings.Cut(line, " ")
+		c := Countdown{Name: strings.TrimSpace(name)}
+		if c.Name == "" {
+			return nil, fmt.Errorf("line %d: missing name: %s", lineNumber, line)
+		}
+		if offset, ok := strings.CutPrefix(strings.ToLower(target), "easter"); ok {
+			c.Easter = true
+			if offset != "" {
+				n, err := strconv.Atoi(offset)
+				if err != nil || (offset[0] != '+' && offset[0] != '-') {
+					return nil, fmt.Errorf("line %d: invalid number of days from Easter, expected like easter+39: %s", lineNumber, target)
+				}
+				c.EasterOffset = n
+			}
+		} else if t, err := time.Parse("2006-01-02", target); err == nil {
+			c.Date = t
+		} else if t, err := time.Parse("01-02", target); err == nil {
+			c.Month, c.Day = t.Month(), t.Day()
+		} else {
+			return nil, fmt.Errorf("line %d: invalid day, expected YYYY-MM-DD, MM-DD or easter: %s", lineNumber, target)
+		}
+		countdowns = append(countdowns, c)
+	}
+	return countdowns, scanner.Err()
+}
+
+// LoadCountdowns reads the days to count down to from the given file, see ParseCountdowns
+func LoadCountdowns(filename string) ([]Countdown, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	countdowns, err := ParseCountdowns(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return countdowns, nil
+}
+
+// dateIn returns the day of the countdown in the given year, at midnight UTC
+func (c Countdown) dateIn(year int) time.Time {
+	if c.Easter {
+		return kal.EasterDay(year).AddDate(0, 0, c.EasterOffset)
+	}
+	return time.Date(year, c.Month, c.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// next returns the first day of the countdown that is on or after the given day,
+// or a zero time if it has already passed
+func (c Countdown) next(from time.Time) time.Time {
+	if !c.Date.IsZero() {
+		if c.Date.Before(from) {
+			return time.Time{}
+		}
+		return c.Date
+	}
+	if t := c.dateIn(from.Year()); !t.Before(from) {
+		return t
+	}
+	return c.dateIn(from.Year() + 1)
+}
+
+// DaysLeft returns the number of days from the given day to the next day of the countdown,
+// or -1 if it has already passed
+func (c Countdown) DaysLeft(from time.Time) int {
+	from = dateOf(from)
+	next := c.next(from)
+	if next.IsZero() {
+		return -1
+	}
+	return int(next.Sub(from).Hours() / 24)
+}
+
+// addCountdowns adds a note with the number of days left to each countdown that is less than
+// the given number of days away, on every day or on the first day of the page
+func addCountdowns(page *Page, countdowns []Countdown, mode CountdownMode, maxDays int) {
+	if maxDays <= 0 {
+		maxDays = defaultCountdownDays
+	}
+	for i := range page.Weeks {
+		for j := range page.Weeks[i].Days {
+			if mode != CountdownEveryDay && (i > 0 || j > 0) {
+				return
+			}
+			day := &page.Weeks[i].Days[j]
+			for _, c := range countdowns {
+				if n := c.DaysLeft(day.Time); n > 0 && n <= maxDays {
+					day.Notes = append(day.Notes, FormatCountdown(n, c.Name))
+				}
+			}
+		}
+	}
+}
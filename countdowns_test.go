@@ -0,0 +1,96 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParseCountdowns(t *testing.T) {
+	countdowns, err := ParseCountdowns(strings.NewReader("# Countdowns\n12-24 Christmas Eve\n2026-06-19 Summer holiday\neaster Easter\nEaster+39 Ascension Day\neaster-7 Palm Sunday\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(countdowns) != 5 {
+		t.Fatalf("expected 5 countdowns, got %+v", countdowns)
+	}
+	if c := countdowns[0]; c.Name != "Christmas Eve" || c.Month != time.December || c.Day != 24 || !c.Date.IsZero() {
+		t.Errorf("unexpected countdown: %+v", c)
+	}
+	if c := countdowns[1]; c.Date.Format("2006-01-02") != "2026-06-19" {
+		t.Errorf("unexpected countdown: %+v", c)
+	}
+	if c := countdowns[3]; !c.Easter || c.EasterOffset != 39 {
+		t.Errorf("unexpected countdown: %+v", c)
+	}
+	if c := countdowns[4]; !c.Easter || c.EasterOffset != -7 {
+		t.Errorf("unexpected countdown: %+v", c)
+	}
+	for _, s := range []string{"12-24", "24.12 Christmas Eve", "easter39 Ascension Day", "easter+x Ascension Day"} {
+		if _, err := ParseCountdowns(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestDaysLeft(t *testing.T) {
+	christmas := Countdown{Name: "Christmas Eve", Month: time.December, Day: 24}
+	if n := christmas.DaysLeft(time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)); n != 23 {
+		t.Errorf("expected 23 days to Christmas Eve, got %d", n)
+	}
+	// After Christmas Eve, the count is to Christmas Eve next year
+	if n := christmas.DaysLeft(time.Date(2025, time.December, 25, 0, 0, 0, 0, time.UTC)); n != 364 {
+		t.Errorf("expected 364 days to Christmas Eve, got %d", n)
+	}
+	// Easter Sunday was on the 20th of April in 2025 and is on the 5th of April in 2026
+	easter := Countdown{Name: "Easter", Easter: true}
+	if n := easter.DaysLeft(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)); n != 19 {
+		t.Errorf("expected 19 days to Easter, got %d", n)
+	}
+	if n := easter.DaysLeft(time.Date(2025, time.April, 21, 0, 0, 0, 0, time.UTC)); n != 349 {
+		t.Errorf("expected 349 days to Easter next year, got %d", n)
+	}
+	ascension := Countdown{Name: "Ascension Day", Easter: true, EasterOffset: 39}
+	if n := ascension.DaysLeft(time.Date(2025, time.May, 28, 0, 0, 0, 0, time.UTC)); n != 1 {
+		t.Errorf("expected 1 day to Ascension Day, got %d", n)
+	}
+	single := Countdown{Name: "Summer holiday", Date: time.Date(2025, time.June, 20, 0, 0, 0, 0, time.UTC)}
+	if n := single.DaysLeft(time.Date(2025, time.June, 21, 0, 0, 0, 0, time.UTC)); n != -1 {
+		t.Errorf("expected the summer holiday to have passed, got %d", n)
+	}
+}
+
+func TestAddCountdowns(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	countdowns := []Countdown{{Name: "Christmas Eve", Month: time.December, Day: 24}, {Name: "Easter", Easter: true}}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 49, Names: []string{"Bob"}, Countdowns: countdowns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only on the first day of the page, and only the countdowns that are less than 31 days away
+	if notes := page.Weeks[0].Days[0].Notes; len(notes) != 1 || notes[0] != FormatCountdown(23, "Christmas Eve") {
+		t.Errorf("expected 23 days to Christmas Eve on the 1st of December, got %v", notes)
+	}
+	if notes := page.Weeks[0].Days[1].Notes; len(notes) != 0 {
+		t.Errorf("expected no countdown on the 2nd of December, got %v", notes)
+	}
+	page, err = buildPage(cal, tmpl, Options{Year: 2025, Week: 51, Names: []string{"Bob"}, Countdowns: countdowns, CountdownMode: CountdownEveryDay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notes := page.Weeks[0].Days[1].Notes; len(notes) != 1 || notes[0] != FormatCountdown(8, "Christmas Eve") {
+		t.Errorf("expected 8 days to Christmas Eve on the 16th of December, got %v", notes)
+	}
+	if notes := page.Weeks[1].Days[1].Notes; len(notes) != 1 || notes[0] != FormatCountdown(1, "Christmas Eve") {
+		t.Errorf("expected 1 day to Christmas Eve on the 23rd of December, got %v", notes)
+	}
+}
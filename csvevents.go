@@ -0,0 +1,230 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVColumns are the columns of a CSV file with events, as names in the header row or as
+// column numbers, counted from 1
+type CSVColumns struct {
+	Date   string
+	Time   string // the start time, the events are all-day events if the column is empty or missing
+	Person string // the rows that the event is shown in, the events are placed like other events if missing
+	Title  string
+}
+
+// DefaultCSVColumns are the columns of a CSV file with events, if no other columns are given
+var DefaultCSVColumns = CSVColumns{Date: "date", Time: "time", Person: "person", Title: "title"}
+
+// ParseCSVColumns parses comma separated columns on the form field=column,
+// like "date=Dato,time=Tid,person=Hvem,title=Hva" or "date=1,title=3". The time and person
+// columns are not used if they are not given, and DefaultCSVColumns are used if s is empty.
+func ParseCSVColumns(s string) (CSVColumns, error) {
+	if strings.TrimSpace(s) == "" {
+		return DefaultCSVColumns, nil
+	}
+	columns := CSVColumns{Date: DefaultCSVColumns.Date, Title: DefaultCSVColumns.Title}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, column, ok := strings.Cut(field, "=")
+		if !ok {
+			return columns, fmt.Errorf("missing = in CSV column: %s", field)
+		}
+		column = strings.TrimSpace(column)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "date":
+			columns.Date = column
+		case "time":
+			columns.Time = column
+		case "person":
+			columns.Person = column
+		case "title":
+			columns.Title = column
+		default:
+			return columns, fmt.Errorf("unknown field in CSV column, expected date, time, person or title: %s", field)
+		}
+	}
+	return columns, nil
+}
+
+// numbered checks if all the columns are given as column numbers, and the CSV file has no header row
+func (c CSVColumns) numbered() bool {
+	for _, column := range []string{c.Date, c.Time, c.Person, c.Title} {
+		if _, err := strconv.Atoi(column); column != "" && err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// indices returns the index of each of the date, time, person and title columns, or -1 if a column is missing
+func (c CSVColumns) indices(header []string) ([4]int, error) {
+	var indices [4]int
+	for i, column := range []string{c.Date, c.Time, c.Person, c.Title} {
+		indices[i] = -1
+		if n, err := strconv.Atoi(column); err == nil {
+			if n < 1 {
+				return indices, fmt.Errorf("invalid column number: %d", n)
+			}
+			indices[i] = n - 1
+			continue
+		}
+		for j, name := range header {
+			if column != "" && strings.EqualFold(strings.TrimSpace(name), column) {
+				indices[i] = j
+				break
+			}
+		}
+	}
+	if indices[0] < 0 {
+		return indices, fmt.Errorf("no date column named %q", c.Date)
+	}
+	if indices[3] < 0 {
+		return indices, fmt.Errorf("no title column named %q", c.Title)
+	}
+	return indices, nil
+}
+
+// parseLocalDate parses a date on the form YYYY-MM-DD, or written the local way, like "17.05.2025"
+func parseLocalDate(s string) (time.Time, error) {
+	for _, layout := range append([]string{"2006-01-02"}, DateLayouts()...) {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %s", s)
+}
+
+// parseTimeOfDay parses a time of day, like "14:30", "14.30" or "2:30 PM", and returns the hour and minute
+func parseTimeOfDay(s string) (int, int, error) {
+	compact := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	for _, layout := range []string{"15:04", "15.04", "3:04PM", "3PM"} {
+		if t, err := time.Parse(layout, compact); err == nil {
+			return t.Hour(), t.Minute(), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid time: %s", s)
+}
+
+// ParseCSVEvents reads events from a CSV file, like a spreadsheet with plans, with a date, a title
+// and optionally a start time and a person on each row, in the given columns. The first row is the
+// header, unless all the columns are given as numbers. The values can be separated by commas,
+// semicolons or tabs. For example:
+//
+//	Date;Time;Person;Title
+//	17.05.2025;;;Children's parade
+//	2025-05-18;14:30;Alice;Dinner at grandma's
+//
+// The dates are on the form YYYY-MM-DD, or written the local way. All the malformed rows are
+// reported, with line numbers.
+func ParseCSVEvents(r io.Reader, columns CSVColumns) ([]Event, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff")) // spreadsheets may start the file with a byte order mark
+	reader := csv.NewReader(bytes.NewReader(data))
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	for _, comma := range []rune{';', '\t'} {
+		if bytes.Count(firstLine, []byte(string(comma))) > bytes.Count(firstLine, []byte(string(reader.Comma))) {
+			reader.Comma = comma
+		}
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if !columns.numbered() {
+		if header, err = reader.Read(); err != nil {
+			if err == io.EOF {
+				return nil, errors.New("the CSV file has no header row")
+			}
+			return nil, err
+		}
+	}
+	indices, err := columns.indices(header)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		events []Event
+		errs   []error
+	)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// The errors of the csv package have line numbers
+			errs = append(errs, err)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		field := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.Join(record, "") == "" {
+			continue
+		}
+		date, clock, person, title := field(indices[0]), field(indices[1]), field(indices[2]), field(indices[3])
+		if date == "" || title == "" {
+			errs = append(errs, fmt.Errorf("line %d: expected a date and a title", line))
+			continue
+		}
+		day, err := parseLocalDate(date)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		e := Event{UID: fmt.Sprintf("csv-%d", line), Summary: title, Start: day, End: day.AddDate(0, 0, 1), AllDay: true}
+		if clock != "" {
+			hour, minute, err := parseTimeOfDay(clock)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+				continue
+			}
+			// Times are in the time zone of the calendar, like in the household events
+			e.Start = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, floating)
+			e.End, e.AllDay = e.Start, false
+		}
+		for _, row := range strings.Split(person, ",") {
+			if row = strings.TrimSpace(row); row != "" {
+				e.Rows = append(e.Rows, row)
+			}
+		}
+		events = append(events, e)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return events, nil
+}
+
+// LoadCSVEvents reads events from the given CSV file, see ParseCSVEvents
+func LoadCSVEvents(filename string, columns CSVColumns) ([]Event, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, err := ParseCSVEvents(f, columns)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return events, nil
+}
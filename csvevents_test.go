@@ -0,0 +1,87 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSVColumns(t *testing.T) {
+	columns, err := ParseCSVColumns("date=Dato, time=Tid, person=Hvem, title=Hva")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if columns != (CSVColumns{Date: "Dato", Time: "Tid", Person: "Hvem", Title: "Hva"}) {
+		t.Errorf("unexpected columns: %+v", columns)
+	}
+	columns, err = ParseCSVColumns("date=1,title=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !columns.numbered() || columns.Time != "" {
+		t.Errorf("expected only numbered columns, got %+v", columns)
+	}
+	if columns, err := ParseCSVColumns(""); err != nil || columns != DefaultCSVColumns {
+		t.Errorf("expected the default columns, got %+v, %v", columns, err)
+	}
+	for _, s := range []string{"date", "place=Sted"} {
+		if _, err := ParseCSVColumns(s); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestParseCSVEvents(t *testing.T) {
+	// The dates can also be written the local way, if the calendar is built with a locale
+	localDate := "2025-05-17"
+	if layouts := DateLayouts(); len(layouts) > 0 {
+		localDate = time.Date(2025, time.May, 17, 0, 0, 0, 0, time.UTC).Format(layouts[0])
+	}
+	data := "\ufeffDato;Tid;Hvem;Hva\n" + localDate + ";;;Parade\n2025-05-18; 14:30 ;Alice;Dinner\n\n2025-05-19;2:30 pm;\"Alice, Bob\";Dentist\n"
+	columns := CSVColumns{Date: "dato", Time: "tid", Person: "hvem", Title: "hva"}
+	events, err := ParseCSVEvents(strings.NewReader(data), columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if e := events[0]; !e.AllDay || e.Summary != "Parade" || !e.Start.Equal(time.Date(2025, time.May, 17, 0, 0, 0, 0, time.UTC)) || len(e.Rows) != 0 {
+		t.Errorf("unexpected all-day event: %+v", e)
+	}
+	if e := events[1]; e.AllDay || e.Start.Hour() != 14 || e.Start.Minute() != 30 || e.Start.Day() != 18 || strings.Join(e.Rows, ",") != "Alice" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[2]; e.Start.Hour() != 14 || strings.Join(e.Rows, ",") != "Alice,Bob" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+
+	// Without a header row, with the columns given as numbers and separated by commas
+	events, err = ParseCSVEvents(strings.NewReader("Dentist,2025-05-19,Alice\n"), CSVColumns{Date: "2", Person: "3", Title: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Summary != "Dentist" || events[0].Rows[0] != "Alice" {
+		t.Errorf("unexpected events: %+v", events)
+	}
+}
+
+func TestParseCSVEventsErrors(t *testing.T) {
+	data := "date,time,title\n2025-02-30,,Bad date\n2025-05-18,25:00,Bad time\n2025-05-19,10:00,Fine\n2025-05-20,,\n"
+	_, err := ParseCSVEvents(strings.NewReader(data), DefaultCSVColumns)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	// All the malformed rows are reported, with line numbers
+	for _, s := range []string{"line 2: invalid date", "line 3: invalid time", "line 5: expected a date and a title"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected %q in the error, got %v", s, err)
+		}
+	}
+	if strings.Contains(err.Error(), "line 4") {
+		t.Errorf("expected no error for line 4, got %v", err)
+	}
+	if _, err := ParseCSVEvents(strings.NewReader("when,what\n2025-05-19,Dentist\n"), DefaultCSVColumns); err == nil {
+		t.Error("expected an error for a missing date column")
+	}
+}
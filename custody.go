@@ -0,0 +1,134 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustodyTurn is a number of days in a row that one person is responsible
+type CustodyTurn struct {
+	Person string
+	Days   int
+}
+
+// CustodyPattern is a repeating sequence of turns, like alternating weeks or 2-2-5-5, that starts
+// at an anchor day. The turn of each day is counted in days from the anchor, so that the pattern
+// is not affected by ISO years with 53 weeks or by where the pages start.
+type CustodyPattern struct {
+	Anchor time.Time // the first day of the first turn, at midnight UTC
+	Turns  []CustodyTurn
+}
+
+// ParseCustodyPatterns reads repeating patterns of who is responsible, one on each line, with the
+// first day of the first turn followed by the turns, each with a name and a number of days:
+//
+//	# Alternating weeks, from monday to monday
+//	2025-01-06 Alice 7, Bob 7
+//	# 2-2-5-5
+//	2025-01-06 Alice 2, Bob 2, Alice 5, Bob 5
+//
+// Empty lines and lines starting with # are skipped.
+func ParseCustodyPatterns(r io.Reader) ([]CustodyPattern, error) {
+	var patterns []CustodyPattern
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		date, turns, _ := strings.Cut(line, " ")
+		anchor, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date, expected YYYY-MM-DD: %s", lineNumber, date)
+		}
+		p := CustodyPattern{Anchor: anchor}
+		for _, turn := range strings.Split(turns, ",") {
+			fields := strings.Fields(turn)
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("line %d: expected a name and a number of days: %s", lineNumber, strings.TrimSpace(turn))
+			}
+			days, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil || days < 1 {
+				return nil, fmt.Errorf("line %d: invalid number of days: %s", lineNumber, fields[len(fields)-1])
+			}
+			p.Turns = append(p.Turns, CustodyTurn{Person: strings.Join(fields[:len(fields)-1], " "), Days: days})
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, scanner.Err()
+}
+
+// LoadCustodyPatterns reads repeating patterns of who is responsible from the given file, see ParseCustodyPatterns
+func LoadCustodyPatterns(filename string) ([]CustodyPattern, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	patterns, err := ParseCustodyPatterns(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return patterns, nil
+}
+
+// cycleDay returns the day within a repeating cycle of the given length that starts at the anchor,
+// from 0 to length-1. Days before the anchor continue the cycle backwards.
+func cycleDay(anchor, t time.Time, length int) int {
+	days := int(dateOf(t).Sub(dateOf(anchor)).Hours() / 24)
+	return ((days % length) + length) % length
+}
+
+// turnAt returns the index of the turn of the given day
+func (p CustodyPattern) turnAt(t time.Time) int {
+	cycle := 0
+	for _, turn := range p.Turns {
+		cycle += turn.Days
+	}
+	days := cycleDay(p.Anchor, t, cycle)
+	for i, turn := range p.Turns {
+		if days < turn.Days {
+			return i
+		}
+		days -= turn.Days
+	}
+	return 0
+}
+
+// PersonAt returns the person that is responsible on the given day
+func (p CustodyPattern) PersonAt(t time.Time) string {
+	if len(p.Turns) == 0 {
+		return ""
+	}
+	return p.Turns[p.turnAt(t)].Person
+}
+
+// addCustody shades the cells of the people that are responsible on each day. For people that
+// do not have a row, their name is shown below the day label where their turn starts, and at
+// the start of each week.
+func addCustody(w *Week, patterns []CustodyPattern, names []string) {
+	for _, p := range patterns {
+		if len(p.Turns) == 0 {
+			continue
+		}
+		for i := range w.Days {
+			day := &w.Days[i]
+			person := p.PersonAt(day.Time)
+			found := false
+			for row, name := range names {
+				if strings.EqualFold(strings.TrimSpace(name), person) {
+					day.Cells[row].Responsible = true
+					found = true
+				}
+			}
+			if !found && (i == 0 || p.PersonAt(day.Time.AddDate(0, 0, -1)) != person) {
+				day.Notes = append(day.Notes, person)
+			}
+		}
+	}
+}
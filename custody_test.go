@@ -0,0 +1,105 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParseCustodyPatterns(t *testing.T) {
+	patterns, err := ParseCustodyPatterns(strings.NewReader("# Alternating weeks\n2025-01-06 Alice 7, Bob 7\n2025-01-06 Alice 2, Bob 2, Alice 5, Bob 5\n2025-01-06 Mary Ann 3, Bob 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 3 {
+		t.Fatalf("expected 3 patterns, got %+v", patterns)
+	}
+	if p := patterns[1]; len(p.Turns) != 4 || p.Turns[2].Person != "Alice" || p.Turns[2].Days != 5 {
+		t.Errorf("unexpected pattern: %+v", p)
+	}
+	if p := patterns[2]; p.Turns[0].Person != "Mary Ann" {
+		t.Errorf("unexpected pattern: %+v", p)
+	}
+	for _, s := range []string{"2025-01-06", "2025-01-06 Alice", "2025-01-06 Alice 0, Bob 7", "06.01.2025 Alice 7, Bob 7"} {
+		if _, err := ParseCustodyPatterns(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestCustodyPersonAt(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	weeks := CustodyPattern{Anchor: day(2026, time.January, 5), Turns: []CustodyTurn{{"Alice", 7}, {"Bob", 7}}}
+	// 2026 has 53 ISO weeks, so weeks 53 and 1 are both odd, but the turns still alternate
+	if p := weeks.PersonAt(day(2026, time.December, 28)); p != "Bob" {
+		t.Errorf("expected Bob in week 53 of 2026, got %s", p)
+	}
+	if p := weeks.PersonAt(day(2027, time.January, 4)); p != "Alice" {
+		t.Errorf("expected Alice in week 1 of 2027, got %s", p)
+	}
+	// Days before the anchor continue the pattern backwards
+	if p := weeks.PersonAt(day(2025, time.December, 29)); p != "Bob" {
+		t.Errorf("expected Bob the week before the anchor, got %s", p)
+	}
+	// 2-2-5-5: Alice on monday and tuesday, Bob on wednesday and thursday, then Alice from friday to tuesday
+	pattern := CustodyPattern{Anchor: day(2025, time.January, 6), Turns: []CustodyTurn{{"Alice", 2}, {"Bob", 2}, {"Alice", 5}, {"Bob", 5}}}
+	var got []string
+	for d := 6; d <= 19; d++ {
+		got = append(got, pattern.PersonAt(day(2025, time.January, d))[:1])
+	}
+	if s := strings.Join(got, ""); s != "AABBAAAAABBBBB" {
+		t.Errorf("unexpected 2-2-5-5 pattern: %s", s)
+	}
+	if p := pattern.PersonAt(day(2025, time.January, 20)); p != "Alice" {
+		t.Errorf("expected the pattern to repeat after 14 days, got %s", p)
+	}
+}
+
+func TestAddCustody(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := ParseCustodyPatterns(strings.NewReader("2026-01-05 Alice 7, Bob 7\n2026-01-05 Dad 3, Mom 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A page that starts in week 53 of 2026 and continues into 2027
+	page, err := buildPage(cal, tmpl, Options{Year: 2026, Week: 53, Names: []string{"Alice", "Bob"}, Custody: patterns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Weeks[1].Year != 2027 || page.Weeks[1].Number != 1 {
+		t.Fatalf("expected week 1 of 2027 on the second half of the page, got week %d of %d", page.Weeks[1].Number, page.Weeks[1].Year)
+	}
+	for i, w := range page.Weeks {
+		responsible := 1 - i // Bob in week 53, Alice in week 1
+		for _, day := range w.Days {
+			if !day.Cells[responsible].Responsible || day.Cells[1-responsible].Responsible {
+				t.Errorf("expected %s to be responsible on %s", page.Names[responsible], day.Date)
+			}
+		}
+	}
+	// Dad and Mom have no rows, so their names are shown where their turns start and at the start of the week
+	var notes []string
+	for _, day := range page.Weeks[0].Days {
+		var names []string
+		for _, note := range day.Notes {
+			if note != day.Holiday {
+				names = append(names, note)
+			}
+		}
+		notes = append(notes, strings.Join(names, "+"))
+	}
+	if s := strings.Join(notes, ","); s != "Dad,,,Mom,,," {
+		t.Errorf("unexpected notes: %s", s)
+	}
+}
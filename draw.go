@@ -4,11 +4,10 @@ import (
 	"math/rand"
 
 	"github.com/fogleman/ln/ln"
-	"github.com/signintech/gopdf"
 )
 
-// DrawLineImage draws an image into the PDF, using only lines
-func DrawLineImage(pdf *gopdf.GoPdf, year, week int, x, y, width, height float64) error {
+// DrawLineImage draws an image onto the given surface, using only lines
+func DrawLineImage(s Surface, year, week int, x, y, width, height float64) error {
 	var (
 		month = MonthNumber(year, week)
 		paths ln.Paths
@@ -63,8 +62,8 @@ func DrawLineImage(pdf *gopdf.GoPdf, year, week int, x, y, width, height float64
 		paths = scene.Render(eye, center, up, width, height, fovy, 0.1, 100, 0.01)
 	}
 
-	pdf.SetStrokeColor(0, 0, 0)
-	pdf.SetLineWidth(0.5)
+	s.SetStrokeColor(0, 0, 0)
+	s.SetLineWidth(0.5)
 
 	px := 0.0
 	py := 0.0
@@ -72,7 +71,7 @@ func DrawLineImage(pdf *gopdf.GoPdf, year, week int, x, y, width, height float64
 	for _, path := range paths {
 		for _, v := range path {
 			if px != 0.0 && py != 0.0 {
-				pdf.Line(x+px, y+py, x+v.X*scale, y+v.Y*scale)
+				s.Line(x+px, y+py, x+v.X*scale, y+v.Y*scale)
 			}
 			px = v.X * scale
 			py = v.Y * scale
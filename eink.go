@@ -0,0 +1,79 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"io"
+	"sort"
+)
+
+// DeviceProfile describes the screen of a display device, like an e-ink display or a tablet
+type DeviceProfile struct {
+	Name       string
+	Width      int // the width of the screen in pixels, in the native orientation of the device
+	Height     int // the height of the screen in pixels, in the native orientation of the device
+	GrayLevels int // the number of shades of gray, or 0 for full color
+}
+
+// DeviceProfiles are the known display devices, by name
+var DeviceProfiles = map[string]DeviceProfile{
+	"eink-7in5":     {Name: "eink-7in5", Width: 800, Height: 480, GrayLevels: 2},
+	"eink-7in5-hd":  {Name: "eink-7in5-hd", Width: 880, Height: 528, GrayLevels: 2},
+	"inkplate-10":   {Name: "inkplate-10", Width: 1200, Height: 825, GrayLevels: 8},
+	"kindle":        {Name: "kindle", Width: 1072, Height: 1448, GrayLevels: 16},
+	"remarkable":    {Name: "remarkable", Width: 1404, Height: 1872, GrayLevels: 16},
+	"boox":          {Name: "boox", Width: 1872, Height: 1404, GrayLevels: 16},
+	"photoframe-hd": {Name: "photoframe-hd", Width: 1920, Height: 1080},
+}
+
+// DeviceProfileNames returns the names of the known display devices, sorted alphabetically
+func DeviceProfileNames() []string {
+	names := make([]string, 0, len(DeviceProfiles))
+	for name := range DeviceProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RasterOptions returns the image settings for this device, rotated clockwise by the given degrees
+func (p DeviceProfile) RasterOptions(rotate int) RasterOptions {
+	return RasterOptions{
+		Width:      p.Width,
+		Height:     p.Height,
+		GrayLevels: p.GrayLevels,
+		Rotate:     rotate,
+	}
+}
+
+// Landscape checks if the page must be laid out in landscape orientation,
+// after the image has been rotated clockwise by the given degrees
+func (p DeviceProfile) Landscape(rotate int) bool {
+	if rotate%180 != 0 {
+		return p.Height > p.Width
+	}
+	return p.Width > p.Height
+}
+
+// GenerateDevicePNG renders a calendar page to a PNG image with the exact resolution and
+// shades of gray of the given device. If no template is given, the built-in "landscape"
+// template is used for devices that are wider than they are tall.
+func GenerateDevicePNG(p DeviceProfile, opts Options, rotate int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteDevicePNG(&buf, p, opts, rotate); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteDevicePNG renders a calendar page for the given device, like GenerateDevicePNG,
+// and writes the PNG image to the given writer
+func WriteDevicePNG(w io.Writer, p DeviceProfile, opts Options, rotate int) error {
+	if opts.Template == nil && p.Landscape(rotate) {
+		tmpl, err := BuiltinTemplate("landscape")
+		if err != nil {
+			return err
+		}
+		opts.Template = tmpl
+	}
+	return WritePNG(w, opts, p.RasterOptions(rotate))
+}
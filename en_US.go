@@ -59,6 +59,60 @@ func DayAndDate(cal kal.Calendar, t time.Time) string {
 	return fmt.Sprintf("%s. %d%s", dayName, day, suffix)
 }
 
+// FormatTime takes a time.Time and returns the time of day as a string on the format "2:30pm"
+func FormatTime(t time.Time) string {
+	return t.Format("3:04pm")
+}
+
+// FormatAge returns an age in years as a string on the form "12 years"
+func FormatAge(years int) string {
+	if years == 1 {
+		return "1 year"
+	}
+	return fmt.Sprintf("%d years", years)
+}
+
+// FormatDayLength returns the time from sunrise to sunset as a string on the form "18h 51m"
+func FormatDayLength(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// FormatClockChange returns how much the clocks are moved as a string on the form "clocks +1h"
+func FormatClockChange(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "−", -d
+	}
+	if d%time.Hour != 0 {
+		return fmt.Sprintf("clocks %s%dm", sign, int(d.Minutes()))
+	}
+	return fmt.Sprintf("clocks %s%dh", sign, int(d.Hours()))
+}
+
+// FormatCountdown returns the number of days left to a day as a string on the form "23 days to Christmas"
+func FormatCountdown(days int, name string) string {
+	if days == 1 {
+		return "1 day to " + name
+	}
+	return fmt.Sprintf("%d days to %s", days, name)
+}
+
+// DateLayouts returns the layouts of dates written the local way, like "05/17/2025"
+func DateLayouts() []string {
+	return []string{"01/02/2006", "1/2/2006"}
+}
+
+// PolarDayString is shown instead of the sunrise and sunset when the sun does not set
+func PolarDayString() string {
+	return "Midnight sun"
+}
+
+// PolarNightString is shown instead of the sunrise and sunset when the sun does not rise
+func PolarNightString() string {
+	return "Polar night"
+}
+
 // NewCalendar returns a new struct that satisfies the kal.Calendar interface
 func NewCalendar() (kal.Calendar, error) {
 	calendar, err := kal.NewCalendar("en_US", true)
@@ -0,0 +1,169 @@
+package kitchencalendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventRule places the events that match a calendar name, a category or an attendee in a row
+type EventRule struct {
+	Field string // "calendar", "category" or "attendee"
+	Value string
+	Row   string // one of the names, or "*" for all rows
+}
+
+// ParseEventRules parses comma separated rules on the form field:value=row,
+// like "calendar:Football=Alice,category:Work=Bob,attendee:bob@example.com=Bob"
+func ParseEventRules(s string) ([]EventRule, error) {
+	var rules []EventRule
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		match, row, ok := strings.Cut(field, "=")
+		if !ok {
+			return nil, fmt.Errorf("missing = in event rule: %s", field)
+		}
+		name, value, ok := strings.Cut(match, ":")
+		if !ok {
+			return nil, fmt.Errorf("missing : in event rule: %s", field)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "calendar", "category", "attendee":
+		default:
+			return nil, fmt.Errorf("unknown field in event rule, expected calendar, category or attendee: %s", field)
+		}
+		rules = append(rules, EventRule{Field: name, Value: strings.TrimSpace(value), Row: strings.TrimSpace(row)})
+	}
+	return rules, nil
+}
+
+// anyEqualFold checks if one of the given strings is equal to s, ignoring case
+func anyEqualFold(xs []string, s string) bool {
+	for _, x := range xs {
+		if strings.EqualFold(x, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches checks if the event has the calendar name, category or attendee of the rule
+func (rule EventRule) matches(e Event) bool {
+	switch rule.Field {
+	case "calendar":
+		return strings.EqualFold(e.Calendar, rule.Value)
+	case "category":
+		return anyEqualFold(e.Categories, rule.Value)
+	case "attendee":
+		return anyEqualFold(e.Attendees, rule.Value)
+	}
+	return false
+}
+
+// eventRows returns the indices of the rows that the event should be shown in.
+// If the event has no rows of its own and no rule matches, an event is shown in the row of
+// a person with the same name as the calendar, a category or an attendee of the event.
+// Events that are not shown in any other row are shown in the shared row, if it is given.
+func eventRows(e Event, rules []EventRule, names []string, sharedRow string) []int {
+	var rows []int
+	add := func(row string) {
+		for i, name := range names {
+			if row == "*" || strings.EqualFold(strings.TrimSpace(name), row) {
+				rows = append(rows, i)
+			}
+		}
+	}
+	for _, row := range e.Rows {
+		add(row)
+	}
+	if len(e.Rows) == 0 {
+		for _, rule := range rules {
+			if rule.matches(e) {
+				add(rule.Row)
+			}
+		}
+	}
+	if len(e.Rows) == 0 && (len(rules) == 0 || len(rows) == 0) {
+		for i, name := range names {
+			name = strings.TrimSpace(name)
+			if strings.EqualFold(e.Calendar, name) || anyEqualFold(e.Categories, name) || anyEqualFold(e.Attendees, name) {
+				rows = append(rows, i)
+			}
+		}
+	}
+	if len(rows) == 0 && sharedRow != "" {
+		add(sharedRow)
+	}
+	// Remove duplicates, in case several rules place the event in the same row
+	unique := rows[:0]
+	seen := make(map[int]bool)
+	for _, row := range rows {
+		if !seen[row] {
+			seen[row] = true
+			unique = append(unique, row)
+		}
+	}
+	return unique
+}
+
+// dateOf returns the date of the given time, at midnight UTC, like the days of the calendar
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// eventLine returns the text that is shown for the event on the given day, at midnight UTC.
+// The start time is shown on the first day of events that are not all-day events, in the given
+// time zone, or in the local time zone if loc is nil.
+// The second return value is false if the event does not take place on that day.
+func eventLine(e Event, day time.Time, loc *time.Location) (string, bool) {
+	start, end := e.Start, e.End
+	if !e.AllDay {
+		start, end = inZone(start, loc), inZone(end, loc)
+	}
+	first := dateOf(start)
+	// The end is exclusive, unless the event ends when it starts
+	last := dateOf(end.Add(-time.Nanosecond))
+	if !end.After(start) || last.Before(first) {
+		last = first
+	}
+	if day.Before(first) || day.After(last) {
+		return "", false
+	}
+	if !e.AllDay && day.Equal(first) {
+		return FormatTime(start) + " " + e.Summary, true
+	}
+	return e.Summary, true
+}
+
+// addEvents adds a line to the cells of the given week for each event that takes place on that day
+func addEvents(w *Week, events []Event, rules []EventRule, names []string, sharedRow string, loc *time.Location) {
+	if len(events) == 0 || len(w.Days) == 0 {
+		return
+	}
+	// Search a bit wider than the week, since the event times may be in any time zone
+	from := w.Days[0].Time.AddDate(0, 0, -1)
+	to := w.Days[len(w.Days)-1].Time.AddDate(0, 0, 2)
+	expanded := ExpandEvents(events, from, to)
+	// All-day events are listed first
+	for _, allDay := range []bool{true, false} {
+		for _, e := range expanded {
+			if e.AllDay != allDay {
+				continue
+			}
+			rows := eventRows(e, rules, names, sharedRow)
+			for i := range w.Days {
+				line, ok := eventLine(e, w.Days[i].Time, loc)
+				if !ok {
+					continue
+				}
+				for _, row := range rows {
+					w.Days[i].Cells[row].Lines = append(w.Days[i].Cells[row].Lines, Line{Text: line, Checkbox: e.Task})
+				}
+			}
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package kitchencalendar
 
 import (
+	"bytes"
 	_ "embed"
 	"errors"
+	"io"
+	"math"
 	"path/filepath"
 
 	"fmt"
@@ -23,11 +26,11 @@ var nunitoRegularData []byte
 //go:embed ttf/nunito/Nunito-Bold.ttf
 var nunitoBoldData []byte
 
-// generateTitle generates the main title of the calendar
-func generateTitle(cal kal.Calendar, year, week int) string {
+// generateTitle generates the main title of the calendar, for the given number of weeks
+func generateTitle(cal kal.Calendar, year, week, weeks int) string {
 	mondayTime := FirstMondayOfWeek(year, week)
 	monthName1 := GetMonthName(cal, mondayTime)
-	week++
+	week += weeks - 1
 	mondayTime = FirstMondayOfWeek(year, week)
 	monthName2 := GetMonthName(cal, mondayTime)
 	if monthName1 == monthName2 {
@@ -44,104 +47,534 @@ func generateWeekHeaderRight(cal kal.Calendar, year, week int) string {
 	return fmt.Sprintf("%s -> %s", FormatDate(cal, mondayTime), FormatDate(cal, sundayTime))
 }
 
-func write(pdf *gopdf.GoPdf, x, y float64, text string, fontName string, fontSize int) error {
-	if err := pdf.SetFont(fontName, "", fontSize); err != nil {
+// isRedDay checks if the given day should be written in bold, because it is a Sunday or a public holiday
+func isRedDay(cal kal.Calendar, t time.Time) bool {
+	return t.Weekday() == time.Sunday || kal.RedDay(cal, t)
+}
+
+func write(s Surface, x, y float64, text string, fontName string, fontSize int) error {
+	if err := s.SetFont(fontName, fontSize); err != nil {
 		return err
 	}
-	pdf.SetXY(x, y)
-	pdf.Cell(nil, text)
-	return nil
+	return s.Text(x, y, text)
+}
+
+// weekHeaderHeight is the height of the week title and the day names above the rows of a week table
+const weekHeaderHeight = 37.0
+
+const (
+	cellFontSize   = 8    // the font size of the events and other lines of text in the cells
+	cellLineHeight = 9.5  // the distance between the lines of text in the cells
+	avgCharWidth   = 0.52 // the approximate width of a character, relative to the font size
+	checkboxSize   = 6.0  // the width and height of the checkboxes in the cells
+	iconSize       = 8.0  // the width and height of the icons in the cells
+	hatchSpacing   = 5.0  // the distance between the lines that shade the cells of periods
+)
+
+// cellLines returns the lines of a cell that fit in the given number of lines.
+// If there are too many, the last line that fits tells how many lines are left out.
+func cellLines(lines []Line, maxLines int) []Line {
+	if len(lines) <= maxLines {
+		return lines
+	}
+	if maxLines <= 0 {
+		return nil
+	}
+	shown := append([]Line{}, lines[:maxLines-1]...)
+	return append(shown, Line{Text: fmt.Sprintf("+%d", len(lines)-maxLines+1)})
+}
+
+// drawCheckbox draws a small square, that can be ticked off, at the start of a line of text in a cell.
+// The sides are filled rectangles, so that the line width of the table is left as it is.
+func drawCheckbox(s Surface, x, y float64) {
+	const side = 0.5
+	x, y = x+0.5, y+1.5
+	s.SetFillColor(0, 0, 0)
+	s.FillRect(x, y, checkboxSize, side)
+	s.FillRect(x, y+checkboxSize-side, checkboxSize, side)
+	s.FillRect(x, y, side, checkboxSize)
+	s.FillRect(x+checkboxSize-side, y, side, checkboxSize)
+}
+
+// drawIcon draws a small icon, like a birthday cake, at the start of a line of text in a cell
+func drawIcon(s Surface, icon string, x, y float64) {
+	s.SetFillColor(0, 0, 0)
+	switch icon {
+	case IconCake:
+		// Two layers of cake and a candle
+		s.FillRect(x+0.5, y+5, 7, 3)
+		s.FillRect(x+1.5, y+3.5, 5, 1)
+		s.FillRect(x+3.5, y+1, 1, 2)
+	case IconNewMoon, IconFirstQuarter, IconFullMoon, IconLastQuarter:
+		drawMoon(s, icon, x+iconSize/2, y+iconSize/2, iconSize/2-0.5)
+	}
+}
+
+// drawMoon draws a phase of the moon as a circle, with the dark part filled, given the center and the radius.
+// The circle is drawn with small filled rectangles, so that the line width is left as it is.
+func drawMoon(s Surface, icon string, cx, cy, radius float64) {
+	const (
+		strip = 0.25 // the height of the filled strips
+		dot   = 0.6  // the size of the dots along the circle
+		dots  = 48
+	)
+	for i := 0; i < dots; i++ {
+		angle := 2 * math.Pi * float64(i) / dots
+		s.FillRect(cx+radius*math.Cos(angle)-dot/2, cy+radius*math.Sin(angle)-dot/2, dot, dot)
+	}
+	if icon == IconFullMoon {
+		return
+	}
+	for dy := -radius; dy < radius; dy += strip {
+		half := math.Sqrt(max(radius*radius-(dy+strip/2)*(dy+strip/2), 0))
+		left, right := cx-half, cx+half
+		switch icon {
+		case IconFirstQuarter:
+			// The left half is dark
+			right = cx
+		case IconLastQuarter:
+			// The right half is dark
+			left = cx
+		}
+		// The strips overlap a little, so that there are no gaps between them
+		s.FillRect(left, cy+dy, right-left, strip*1.5)
+	}
+}
+
+// drawHatch shades a rectangle with light diagonal lines, from the lower left to the upper right
+func drawHatch(s Surface, x, y, width, height float64) {
+	s.SetStrokeColor(190, 190, 190)
+	for k := hatchSpacing; k < width+height; k += hatchSpacing {
+		// The line is where the distance from the left side plus the distance from the top is k
+		t1, t2 := max(0, k-height), min(width, k)
+		s.Line(x+t1, y+k-t1, x+t2, y+k-t2)
+	}
+	s.SetStrokeColor(0, 0, 0)
+}
+
+// weekNotesHeight returns the height of the notes below the day labels of a week,
+// which is the height of the longest list of notes. The phase of the moon is shown
+// at the end of the first line of notes.
+func weekNotesHeight(w Week) float64 {
+	maxNotes := 0
+	for _, day := range w.Days {
+		maxNotes = max(maxNotes, len(dayNotes(day)))
+		if day.Moon != "" {
+			maxNotes = max(maxNotes, 1)
+		}
+	}
+	return float64(maxNotes) * cellLineHeight
 }
 
-// draw a week into the PDF
-func drawWeek(pdf *gopdf.GoPdf, cal kal.Calendar, year, week int, x, y *float64, width float64, names []string) error {
-	tableHeight := 300.0
+// fitText shortens the text so that it approximately fits within the given width
+func fitText(text string, width float64, fontSize int) string {
+	maxChars := int(width / (avgCharWidth * float64(fontSize)))
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	if maxChars <= 1 {
+		return ""
+	}
+	return strings.TrimSpace(string(runes[:maxChars-1])) + "…"
+}
+
+// draw a week onto the given surface, with the names of the rows in the font and size of the given style
+func drawWeek(s Surface, w Week, names []string, legends [][]string, style Style, x, y *float64, width, height float64) error {
+	tableHeight := height - weekHeaderHeight
 
 	// Draw the left vertical lines of the table
-	pdf.Line(*x, *y+20, *x, *y+tableHeight+37.2)
+	s.Line(*x, *y+20, *x, *y+tableHeight+37.2)
 
 	// Draw the right vertical lines of the table
-	pdf.Line(*x+width, *y+20, *x+width, *y+tableHeight+37.2)
-
-	// Generate the titles for this week
-	headerLeft := WeekString(week)
-	headerRight := generateWeekHeaderRight(cal, year, week)
+	s.Line(*x+width, *y+20, *x+width, *y+tableHeight+37.2)
 
 	// Draw the header for the 1st week
-	if err := write(pdf, *x, *y, headerLeft, "bold", 14); err != nil {
+	if err := write(s, *x, *y, w.Label, "bold", 14); err != nil {
 		return err
 	}
-	approxHeaderRightWidth := float64(len(headerRight)) * 4.9
-	if err := write(pdf, width-approxHeaderRightWidth, *y, headerRight, "regular", 14); err != nil {
+	// The dates are right-aligned with the table, within the region of the week
+	approxHeaderRightWidth := float64(len([]rune(w.Period)))*avgCharWidth*14 + 2
+	if err := write(s, *x+width-approxHeaderRightWidth, *y, w.Period, "regular", 14); err != nil {
 		return err
 	}
 
 	// Draw a horizontal line
 	*y += 20
-	pdf.Line(*x-0.2, *y, *x+width+0.2, *y)
-
-	// Find monday and sunday
-	mondayTime := FirstMondayOfWeek(year, week)
-	sundayTime := FirstSundayAfter(mondayTime)
+	s.Line(*x-0.2, *y, *x+width+0.2, *y)
 
 	// Draw the week names and vertical lines for the 1st week
 	originalX := *x
 	*x += 70
 	cellWidth := width / 8.0
-	i := 1
-	err := IterateDays(mondayTime, sundayTime, func(t time.Time) error {
-		text := DayAndDate(cal, t)
-
+	for i, day := range w.Days {
 		fontName := "regular"
-		if isRedDay := kal.RedDay(cal, t); t.Weekday() == time.Sunday || isRedDay { // Red day
+		if day.Red {
 			fontName = "bold"
 		}
 
 		fontSize := 11
-		if err := write(pdf, originalX+float64(i)*cellWidth+2, *y, text, fontName, fontSize); err != nil {
+		if err := write(s, originalX+float64(i+1)*cellWidth+2, *y, day.Label, fontName, fontSize); err != nil {
 			return err
 		}
+		// Draw the holiday names, birthdays and other notes below the day label,
+		// with the phase of the moon at the end of the first line
+		for j, note := range dayNotes(day) {
+			noteFont, noteWidth := "regular", cellWidth-4
+			if j == 0 && day.Moon != "" {
+				noteWidth -= iconSize + 2
+			}
+			if note.Bold {
+				// Bold text is wider than the average character width
+				noteFont, noteWidth = "bold", noteWidth*0.9
+			}
+			if err := write(s, originalX+float64(i+1)*cellWidth+2, *y+15+float64(j)*cellLineHeight, fitText(note.Text, noteWidth, cellFontSize), noteFont, cellFontSize); err != nil {
+				return err
+			}
+		}
+		if day.Moon != "" {
+			drawIcon(s, day.Moon, originalX+float64(i+2)*cellWidth-iconSize-2, *y+15)
+		}
 		// Draw the vertical line
-		pdf.Line(originalX+float64(i)*cellWidth, *y, originalX+float64(i)*cellWidth, *y+tableHeight+17.3)
-		i++
-		return nil
-	})
-	if err != nil {
-		return err
+		s.Line(originalX+float64(i+1)*cellWidth, *y, originalX+float64(i+1)*cellWidth, *y+tableHeight+17.3)
 	}
 	*x = originalX
 
-	// Draw a horizontal line
-	*y += 15
-	pdf.Line(*x, *y, *x+width, *y)
+	// Draw a horizontal line, below the notes
+	notesHeight := weekNotesHeight(w)
+	*y += 15 + notesHeight
+	s.Line(*x, *y, *x+width, *y)
 
 	if len(names) == 0 {
 		return errors.New("the given slice of names is empty")
 	}
 
-	nameHeight := tableHeight / float64(len(names))
+	nameHeight := (tableHeight - notesHeight) / float64(len(names))
 
 	// Draw the names of the people that should use this calendar, with horizontal lines
+	rowTop := *y
 	*y += 2
-	for _, text := range names {
-		// Draw the names
-		fontName := "regular"
-		fontSize := 12
-		if err := write(pdf, *x+3, *y+1, text, fontName, fontSize); err != nil {
-			return err
+	for row, text := range names {
+		// Draw the names, wrapped to fit in the name column, like the names of tracker rows
+		fontName, fontSize := style.Font, style.Size
+		lineHeight := float64(fontSize) + 2
+		nameLines := wrapLabel(text, max(int((cellWidth-6)/(avgCharWidth*float64(fontSize))), 2))
+		for j, line := range nameLines {
+			if err := write(s, *x+3, *y+1+float64(j)*lineHeight, line, fontName, fontSize); err != nil {
+				return err
+			}
+		}
+		// Draw the legend below the name, like the meaning of the shift codes
+		if row < len(legends) {
+			for j, legend := range legends[row] {
+				legendY := *y + lineHeight + 2 + float64(len(nameLines)-1)*lineHeight + float64(j)*cellLineHeight
+				if legendY+cellLineHeight > *y+nameHeight {
+					break
+				}
+				if err := write(s, *x+3, legendY, fitText(legend, cellWidth-6, cellFontSize), "regular", cellFontSize); err != nil {
+					return err
+				}
+			}
+		}
+		// Draw the events and other lines of text in the cells of this person
+		for i, day := range w.Days {
+			if row >= len(day.Cells) {
+				continue
+			}
+			if day.Cells[row].Responsible {
+				// A light gray background, within the lines of the table
+				s.SetFillColor(230, 230, 230)
+				s.FillRect(originalX+float64(i+1)*cellWidth+1, rowTop+1, cellWidth-2, *y+nameHeight-rowTop-2)
+				s.SetFillColor(0, 0, 0)
+			}
+			if day.Cells[row].Shaded {
+				// Keep the hatch within the lines of the table
+				drawHatch(s, originalX+float64(i+1)*cellWidth+1, rowTop+1, cellWidth-2, *y+nameHeight-rowTop-2)
+			}
+			// The shift code is printed in the upper right corner of the cell
+			shiftWidth := 0.0
+			if shift := day.Cells[row].Shift; shift != "" {
+				// Bold text is wider than the average character width
+				shiftWidth = float64(len([]rune(shift)))*avgCharWidth*cellFontSize/0.9 + 2
+				if err := write(s, originalX+float64(i+2)*cellWidth-shiftWidth-2, *y+1, shift, "bold", cellFontSize); err != nil {
+					return err
+				}
+			}
+			lines := cellLines(day.Cells[row].Lines, int((nameHeight-2)/cellLineHeight))
+			for j, line := range lines {
+				lineX, lineY := originalX+float64(i+1)*cellWidth+2, *y+1+float64(j)*cellLineHeight
+				textWidth := cellWidth - 4
+				if j == 0 {
+					textWidth -= shiftWidth
+				}
+				if line.Checkbox {
+					drawCheckbox(s, lineX, lineY)
+					lineX += checkboxSize + 3
+					textWidth -= checkboxSize + 3
+				}
+				if line.Icon != "" {
+					drawIcon(s, line.Icon, lineX, lineY)
+					lineX += iconSize + 2
+					textWidth -= iconSize + 2
+				}
+				if err := write(s, lineX, lineY, fitText(line.Text, textWidth, cellFontSize), "regular", cellFontSize); err != nil {
+					return err
+				}
+			}
 		}
 		*y += nameHeight
-		pdf.Line(*x, *y, *x+width, *y)
+		s.Line(*x, *y, *x+width, *y)
+		rowTop = *y
 	}
 
 	return nil
 }
 
+// Options are the settings used when generating a calendar
+type Options struct {
+	Year     int
+	Week     int
+	Names    []string
+	Drawing  bool
+	Template *Template // the built-in default template is used if this is nil
+
+	Events       []Event        // appointments that are shown in the rows of the people they belong to
+	EventRules   []EventRule    // which rows the events are shown in
+	SharedRow    string         // the name of an extra row, for the events that are not shown in any of the other rows
+	Chores       []Chore        // household tasks that the people take turns doing
+	Birthdays    []Birthday     // birthdays and anniversaries that are shown every year
+	LeapDay      LeapDayPolicy  // the day that birthdays on the 29th of February are shown in other years
+	Periods      []Period       // named ranges of days that are shaded, like school holidays
+	HolidayNotes bool           // show the names of the public holidays below the day labels
+	MoonPhases   bool           // draw the new moons, first quarters, full moons and last quarters in the day headers
+	Sun          SunDisplay     // show the sunrise and sunset, or the day length, below the day labels
+	Latitude     float64        // the location used for the sunrise and sunset, in degrees north
+	Longitude    float64        // the location used for the sunrise and sunset, in degrees east
+	TimeZone     *time.Location // the time zone of the events, the moon phases, the sunrise and sunset and the clock changes, or the local time zone if nil
+
+	ClockChanges bool     // mark the days when the clocks are moved to or from daylight saving time
+	NameDays     NameDays // the names that are celebrated on each day, shown below the day labels
+
+	Custody []CustodyPattern // repeating patterns of who is responsible, like alternating weeks
+	Shifts  []ShiftPattern   // repeating patterns of shifts, like day and night shifts
+
+	Trackers []Tracker // rows with boxes to tick off each day, like for vitamins or medicine
+
+	Countdowns    []Countdown   // days to count down to, like Christmas Eve
+	CountdownMode CountdownMode // show the countdowns on every day, or on the first day of each page
+	CountdownDays int           // how many days before a day the countdown starts, or 31 if 0
+}
+
+// template returns the template that should be used for these options
+func (opts *Options) template() (*Template, error) {
+	if opts.Template != nil {
+		return opts.Template, nil
+	}
+	return DefaultTemplate()
+}
+
+// pageSize returns the page size for the given template
+func pageSize(tmpl *Template) gopdf.Rect {
+	if tmpl.Width > 0 && tmpl.Height > 0 {
+		return gopdf.Rect{W: tmpl.Width, H: tmpl.Height}
+	}
+	size := tmpl.PageSize
+	if size == "" {
+		size = paperSize
+	}
+	switch strings.ToLower(strings.TrimSpace(size)) {
+	case "letter":
+		return *gopdf.PageSizeLetter
+	default:
+		return *gopdf.PageSizeA4
+	}
+}
+
+// drawRegions draws all the regions of a template onto the given surface
+func drawRegions(s Surface, cal kal.Calendar, tmpl *Template, opts Options) error {
+	page, err := buildPage(cal, tmpl, opts)
+	if err != nil {
+		return err
+	}
+	for _, r := range tmpl.Regions {
+		switch r.Type {
+		case RegionTitle:
+			style := tmpl.style(r, Style{Font: "bold", Size: 24})
+			if err := write(s, r.X, r.Y, page.Title, style.Font, style.Size); err != nil {
+				return err
+			}
+		case RegionDrawing:
+			if opts.Drawing {
+				if err := DrawLineImage(s, opts.Year, opts.Week, r.X, r.Y, r.Width, r.Height); err != nil {
+					return err
+				}
+			}
+		case RegionWeek:
+			style := tmpl.style(r, Style{Font: "regular", Size: 12, LineWidth: 1.0})
+			s.SetLineWidth(style.LineWidth)
+			x, y := r.X, r.Y
+			if err := drawWeek(s, page.Weeks[r.Week], page.Names, page.Legends, style, &x, &y, r.Width, r.Height); err != nil {
+				return err
+			}
+		case RegionNotes:
+			if err := drawNotes(s, tmpl, r); err != nil {
+				return err
+			}
+		case RegionMiniMonth:
+			if err := drawMiniMonth(s, cal, tmpl, r, opts.Year, opts.Week); err != nil {
+				return err
+			}
+		case RegionText:
+			style := tmpl.style(r, Style{Font: "regular", Size: 12})
+			for i, line := range strings.Split(r.Text, "\n") {
+				if err := write(s, r.X, r.Y+float64(i)*float64(style.Size)*1.3, line, style.Font, style.Size); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// drawNotes draws a labeled area with ruled lines for writing notes by hand
+func drawNotes(s Surface, tmpl *Template, r Region) error {
+	style := tmpl.style(r, Style{Font: "bold", Size: 12, LineWidth: 0.5})
+	top := r.Y
+	if r.Text != "" {
+		if err := write(s, r.X, r.Y, r.Text, style.Font, style.Size); err != nil {
+			return err
+		}
+		top += float64(style.Size) * 1.5
+	}
+	lines := r.Lines
+	if lines <= 0 {
+		lines = 5
+	}
+	s.SetLineWidth(style.LineWidth)
+	spacing := (r.Y + r.Height - top) / float64(lines)
+	for i := 1; i <= lines; i++ {
+		ly := top + float64(i)*spacing
+		s.Line(r.X, ly, r.X+r.Width, ly)
+	}
+	return nil
+}
+
+// drawMiniMonth draws a small overview of a month, with red days in bold
+func drawMiniMonth(s Surface, cal kal.Calendar, tmpl *Template, r Region, year, week int) error {
+	style := tmpl.style(r, Style{Font: "regular", Size: 8})
+	mondayTime := FirstMondayOfWeek(year, week)
+	first := time.Date(mondayTime.Year(), mondayTime.Month()+time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+
+	// The month name, one row for the day names and up to six rows of weeks
+	rowHeight := r.Height / 8.0
+	cellWidth := r.Width / 7.0
+	title := fmt.Sprintf("%s %d", GetMonthName(cal, first), first.Year())
+	if err := write(s, r.X, r.Y, title, "bold", style.Size); err != nil {
+		return err
+	}
+	for i, dayName := range strings.Fields(kal.TwoLetterDays(cal, true)) {
+		if err := write(s, r.X+float64(i)*cellWidth, r.Y+rowHeight, capitalize(dayName), style.Font, style.Size); err != nil {
+			return err
+		}
+	}
+	row := 0
+	return IterateDays(first, last, func(t time.Time) error {
+		column := (int(t.Weekday()) + 6) % 7 // monday first
+		if column == 0 && t.Day() != 1 {
+			row++
+		}
+		fontName := style.Font
+		if isRedDay(cal, t) {
+			fontName = "bold"
+		}
+		return write(s, r.X+float64(column)*cellWidth, r.Y+float64(row+2)*rowHeight, fmt.Sprintf("%d", t.Day()), fontName, style.Size)
+	})
+}
+
+// GeneratePDF generates a PDF calendar for the given year and week, using the default template
 func GeneratePDF(year, week int, names []string, drawing bool) ([]byte, error) {
-	cal, err := NewCalendar()
+	return GeneratePDFWithOptions(Options{Year: year, Week: week, Names: names, Drawing: drawing})
+}
+
+// GeneratePDFWithOptions generates a PDF calendar, laid out by the template in the given options
+func GeneratePDFWithOptions(opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, opts); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WritePDF writes a PDF calendar to the given writer, laid out by the template in the given options.
+// The PDF is made in memory before it is written.
+func WritePDF(w io.Writer, opts Options) error {
+	return writePDFPages(w, []Options{opts})
+}
+
+// PagesForRange returns the options for each page that is needed for showing all days from the
+// given date to the given date (inclusive). The first page starts with the week of the from date.
+// The year and week of the given options are replaced, the other fields are kept.
+func PagesForRange(opts Options, from, to time.Time) ([]Options, error) {
+	tmpl, err := opts.template()
 	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, errors.New("the end of the date range is before the start")
+	}
+	var pages []Options
+	// Go from monday to monday, using the ISO year and week, so that weeks that span
+	// two years are numbered correctly
+	monday := FirstMondayOfWeek(from.ISOWeek())
+	for !monday.After(to) {
+		page := opts
+		page.Year, page.Week = monday.ISOWeek()
+		pages = append(pages, page)
+		monday = monday.AddDate(0, 0, 7*tmpl.Weeks())
+	}
+	return pages, nil
+}
+
+// GenerateRangePDF generates a single PDF with one page after the other, with all weeks
+// from the given date to the given date (inclusive), laid out by the template in the given options
+func GenerateRangePDF(opts Options, from, to time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteRangePDF(&buf, opts, from, to); err != nil {
 		return []byte{}, err
 	}
+	return buf.Bytes(), nil
+}
+
+// WriteRangePDF writes a single PDF with all weeks from the given date to the given date
+// (inclusive) to the given writer, laid out by the template in the given options.
+// All the pages are made in memory before the PDF is written, so the memory use grows with the range.
+func WriteRangePDF(w io.Writer, opts Options, from, to time.Time) error {
+	pages, err := PagesForRange(opts, from, to)
+	if err != nil {
+		return err
+	}
+	return writePDFPages(w, pages)
+}
+
+// writePDFPages writes a PDF with one page for each of the given options to the given writer.
+// Each page is laid out by its own template, and has the page size of that template.
+func writePDFPages(w io.Writer, pages []Options) error {
+	cal, err := NewCalendar()
+	if err != nil {
+		return err
+	}
+
+	if len(pages) == 0 {
+		return errors.New("no pages to generate")
+	}
+
+	templates := make([]*Template, len(pages))
+	for i, opts := range pages {
+		if templates[i], err = opts.template(); err != nil {
+			return err
+		}
+	}
 
 	// Got all needed information, generate and output the PDF
 
@@ -149,78 +582,49 @@ func GeneratePDF(year, week int, names []string, drawing bool) ([]byte, error) {
 
 	// Initialize and use a config struct
 	var c gopdf.Config
-	switch strings.TrimSpace(paperSize) {
-	case "letter":
-		c.PageSize = *gopdf.PageSizeLetter
-	default:
-		c.PageSize = *gopdf.PageSizeA4
-	}
+	c.PageSize = pageSize(templates[0])
 	pdf.Start(c)
 
-	pdf.AddPage()
-
 	tempdir := env.Str("TMPDIR", "/tmp")
 
 	nunitoRegularFilename := filepath.Join(tempdir, "Nunito-Regular.ttf")
 	if !exists(nunitoRegularFilename) {
 		if err := os.WriteFile(nunitoRegularFilename, nunitoRegularData, 0o664); err != nil {
-			return []byte{}, fmt.Errorf("could not write to %s: %w", nunitoRegularFilename, err)
+			return fmt.Errorf("could not write to %s: %w", nunitoRegularFilename, err)
 		}
 	}
 	if !exists(nunitoRegularFilename) {
-		return []byte{}, fmt.Errorf("could not write to %s", nunitoRegularFilename)
+		return fmt.Errorf("could not write to %s", nunitoRegularFilename)
 	}
 	defer os.Remove(nunitoRegularFilename)
 
 	nunitoBoldFilename := filepath.Join(tempdir, "Nunito-Bold.ttf")
 	if !exists(nunitoBoldFilename) {
 		if err := os.WriteFile(nunitoBoldFilename, nunitoBoldData, 0o664); err != nil {
-			return []byte{}, fmt.Errorf("could not write to %s: %w", nunitoBoldFilename, err)
+			return fmt.Errorf("could not write to %s: %w", nunitoBoldFilename, err)
 		}
 	}
 	if !exists(nunitoBoldFilename) {
-		return []byte{}, fmt.Errorf("could not write to %s", nunitoBoldFilename)
+		return fmt.Errorf("could not write to %s", nunitoBoldFilename)
 	}
 	defer os.Remove(nunitoBoldFilename)
 
 	if err := pdf.AddTTFFont("regular", nunitoRegularFilename); err != nil {
-		return []byte{}, err
+		return err
 	}
 
 	if err := pdf.AddTTFFont("bold", nunitoBoldFilename); err != nil {
-		return []byte{}, err
-	}
-
-	y := 35.0
-	x := 35.0
-	width := 538.0
-
-	// Draw the month and year title
-	title := generateTitle(cal, year, week)
-	if err := write(&pdf, x, y, title, "bold", 24); err != nil {
-		return []byte{}, err
-	}
-
-	if drawing {
-		DrawLineImage(&pdf, year, week, width-40, y-10, 70, 70)
-	}
-
-	// Set the line width for the weeks and tables
-	pdf.SetLineWidth(1.0)
-
-	// Draw the first week
-	y += 75
-	if err := drawWeek(&pdf, cal, year, week, &x, &y, width, names); err != nil {
-		return []byte{}, err
+		return err
 	}
 
-	week++
-
-	// Draw the second week
-	y += 20
-	if err := drawWeek(&pdf, cal, year, week, &x, &y, width, names); err != nil {
-		return []byte{}, err
+	for i, opts := range pages {
+		size := pageSize(templates[i])
+		pdf.AddPageWithOption(gopdf.PageOption{PageSize: &size})
+		if err := drawRegions(NewPDFSurface(&pdf), cal, templates[i], opts); err != nil {
+			return err
+		}
 	}
 
-	return pdf.GetBytesPdf(), nil
+	_, err = pdf.WriteTo(w)
+	return err
 }
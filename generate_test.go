@@ -0,0 +1,70 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPagesForRange(t *testing.T) {
+	// 2026 has 53 ISO weeks, and the 1st of January 2027 is in week 53
+	from := time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2027, 1, 24, 0, 0, 0, 0, time.UTC)
+	pages, err := PagesForRange(Options{Names: []string{"Bob"}}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][2]int{{2026, 51}, {2026, 53}, {2027, 2}}
+	if len(pages) != len(expected) {
+		t.Fatalf("expected %d pages, got %d", len(expected), len(pages))
+	}
+	for i, page := range pages {
+		if page.Year != expected[i][0] || page.Week != expected[i][1] {
+			t.Errorf("page %d: expected week %d of %d, got week %d of %d", i+1, expected[i][1], expected[i][0], page.Week, page.Year)
+		}
+		if len(page.Names) != 1 {
+			t.Errorf("page %d: expected the names to be kept", i+1)
+		}
+	}
+	if _, err := PagesForRange(Options{}, to, from); err == nil {
+		t.Error("expected an error when the range ends before it starts")
+	}
+}
+
+func TestWritePDFPagesTemplates(t *testing.T) {
+	if _, err := NewCalendar(); err != nil {
+		t.Skip(err) // built without a locale
+	}
+	landscape, err := BuiltinTemplate("landscape")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A portrait page followed by a landscape page
+	pages := []Options{{Year: 2025, Week: 20, Names: []string{"Bob"}}, {Year: 2025, Week: 22, Names: []string{"Bob"}, Template: landscape}}
+	var buf bytes.Buffer
+	if err := writePDFPages(&buf, pages); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "/MediaBox [ 0 0 842.00 595.00 ]") {
+		t.Error("expected the second page to have the page size of its own template")
+	}
+}
+
+func TestWeekStyle(t *testing.T) {
+	if _, err := NewCalendar(); err != nil {
+		t.Skip(err) // built without a locale
+	}
+	tmpl, err := ParseTemplate([]byte(`{"styles": {"table": {"font": "bold", "size": 17}},
+		"regions": [{"type": "week", "x": 10, "y": 10, "width": 500, "height": 300, "style": "table"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := GenerateSVGWithOptions(Options{Year: 2025, Week: 20, Names: []string{"Bob"}, Template: tmpl})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `font-weight="bold" font-size="17" fill="#000000" xml:space="preserve">Bob</text>`) {
+		t.Errorf("expected the names in the font and size of the week style, got:\n%s", data)
+	}
+}
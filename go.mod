@@ -5,16 +5,16 @@ go 1.23.0
 toolchain go1.24.2
 
 require (
+	github.com/fogleman/gg v1.3.0
 	github.com/fogleman/ln v0.0.0-20170223135521-12e6c6e74459
+	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/signintech/gopdf v0.32.0
 	github.com/xyproto/env/v2 v2.5.3
 	github.com/xyproto/kal v1.3.0
+	golang.org/x/image v0.27.0
 )
 
 require (
-	github.com/fogleman/gg v1.3.0 // indirect
-	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0 // indirect
 	github.com/phpdave11/gofpdi v1.0.15 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
-	golang.org/x/image v0.27.0 // indirect
 )
@@ -0,0 +1,309 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// htmlHead is the start of the HTML document. The page size and fonts are filled in with fmt.
+const htmlHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1.0">
+<title>%s</title>
+<style>
+@font-face { font-family: "Nunito"; font-weight: normal; src: url(data:font/ttf;base64,%s) format("truetype"); }
+@font-face { font-family: "Nunito"; font-weight: bold; src: url(data:font/ttf;base64,%s) format("truetype"); }
+@page { size: %.2fpt %.2fpt; margin: 0; }
+html, body { margin: 0; padding: 0; background: #ffffff; }
+body { font-family: "Nunito", sans-serif; color: #000000; }
+.page { position: relative; width: %.2fpt; height: %.2fpt; transform-origin: top left; overflow: hidden; }
+.region { position: absolute; box-sizing: border-box; white-space: nowrap; }
+.weekheader { display: flex; justify-content: space-between; height: 20pt; font-size: 14pt; line-height: 1; }
+.weekheader .week { font-weight: bold; }
+table { border-collapse: collapse; table-layout: fixed; width: 100%%; }
+.week table td, .week table th { border: 1pt solid #000000; padding: 0 2pt; vertical-align: top; overflow: hidden; text-align: left; }
+.week table th { height: 15pt; font-size: 11pt; font-weight: normal; line-height: 1.3; }
+.week table th.red { font-weight: bold; }
+.week table td { font-size: 12pt; }
+.week table td:first-child { white-space: normal; }
+.week table td .line { font-size: 8pt; line-height: 9.5pt; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.week table th .moon { float: right; }
+.week table th .note { font-size: 8pt; line-height: 9.5pt; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.week table td.shaded { background: repeating-linear-gradient(-45deg, transparent 0 4.3pt, #bebebe 4.3pt 5pt); }
+.week table td.responsible { background-color: #e6e6e6; }
+.week table td .shift { float: right; font-size: 8pt; line-height: 9.5pt; font-weight: bold; }
+.week table td .legend { font-weight: normal; font-size: 8pt; line-height: 9.5pt; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.week table td .icon { margin-right: 2pt; }
+.week table td .checkbox { display: inline-block; width: 6pt; height: 6pt; border: 0.5pt solid #000000; margin-right: 3pt; vertical-align: -0.5pt; }
+.minimonth td, .minimonth th { text-align: left; padding: 0; font-weight: normal; }
+.minimonth .red { font-weight: bold; }
+.minimonth .title { font-weight: bold; }
+.notes .line { border-bottom: 0.5pt solid #000000; box-sizing: border-box; }
+.notes .label { font-weight: bold; }
+@media screen { body { display: flex; justify-content: center; } }
+@media print { .page { transform: none !important; } }
+</style>
+</head>
+<body>
+<div class="page" id="page">
+`
+
+// htmlFoot is the end of the HTML document, with a script that scales the page to fit the screen
+const htmlFoot = `</div>
+<script>
+function fit() {
+  var page = document.getElementById("page");
+  var scale = Math.min(window.innerWidth / page.offsetWidth, window.innerHeight / page.offsetHeight);
+  page.style.transform = "scale(" + scale + ")";
+  document.body.style.height = (page.offsetHeight * scale) + "px";
+}
+window.addEventListener("resize", fit);
+fit();
+</script>
+</body>
+</html>
+`
+
+// regionStyle returns the CSS for positioning a region on the page
+func regionStyle(r Region) string {
+	style := fmt.Sprintf("left: %.2fpt; top: %.2fpt;", r.X, r.Y)
+	if r.Width > 0 {
+		style += fmt.Sprintf(" width: %.2fpt;", r.Width)
+	}
+	if r.Height > 0 {
+		style += fmt.Sprintf(" height: %.2fpt;", r.Height)
+	}
+	return style
+}
+
+// fontStyle returns the CSS for the font of a template style
+func fontStyle(style Style) string {
+	weight := "normal"
+	if style.Font == "bold" {
+		weight = "bold"
+	}
+	return fmt.Sprintf(" font-weight: %s; font-size: %dpt; line-height: 1.3;", weight, style.Size)
+}
+
+// writeHTMLWeek writes a week as an HTML table, with the same columns and rows as drawWeek,
+// and the names of the rows in the font and size of the given style
+func writeHTMLWeek(buf *bufio.Writer, w Week, names []string, legends [][]string, style Style, r Region) {
+	fmt.Fprintf(buf, "<div class=\"region week\" style=\"%s\">\n", regionStyle(r))
+	fmt.Fprintf(buf, "<div class=\"weekheader\"><span class=\"week\">%s</span><span>%s</span></div>\n",
+		html.EscapeString(w.Label), html.EscapeString(w.Period))
+	buf.WriteString("<table>\n<tr><th></th>")
+	for _, day := range w.Days {
+		class := ""
+		if day.Red {
+			class = " class=\"red\""
+		}
+		fmt.Fprintf(buf, "<th%s>%s", class, html.EscapeString(day.Label))
+		// The phase of the moon is shown at the end of the first line of notes
+		notes := dayNotes(day)
+		if day.Moon != "" && len(notes) == 0 {
+			notes = []dayNote{{}}
+		}
+		for j, note := range notes {
+			moon := ""
+			if icon, ok := textIcons[day.Moon]; ok && j == 0 {
+				moon = "<span class=\"moon\">" + icon + "</span>"
+			}
+			text := html.EscapeString(note.Text)
+			if note.Bold {
+				text = "<strong>" + text + "</strong>"
+			}
+			fmt.Fprintf(buf, "<div class=\"note\">%s%s</div>", moon, text)
+		}
+		buf.WriteString("</th>")
+	}
+	buf.WriteString("</tr>\n")
+	nameHeight := (r.Height - weekHeaderHeight - weekNotesHeight(w)) / float64(len(names))
+	maxLines := int((nameHeight - 2) / cellLineHeight)
+	for row, name := range names {
+		fmt.Fprintf(buf, "<tr style=\"height: %.2fpt;\"><td style=\"%s\">%s", nameHeight, strings.TrimSpace(fontStyle(style)), html.EscapeString(name))
+		if row < len(legends) {
+			for _, legend := range legends[row] {
+				fmt.Fprintf(buf, "<div class=\"legend\">%s</div>", html.EscapeString(legend))
+			}
+		}
+		buf.WriteString("</td>")
+		for _, day := range w.Days {
+			var classes []string
+			if row < len(day.Cells) && day.Cells[row].Responsible {
+				classes = append(classes, "responsible")
+			}
+			if row < len(day.Cells) && day.Cells[row].Shaded {
+				classes = append(classes, "shaded")
+			}
+			if len(classes) > 0 {
+				fmt.Fprintf(buf, "<td class=\"%s\">", strings.Join(classes, " "))
+			} else {
+				buf.WriteString("<td>")
+			}
+			if row < len(day.Cells) {
+				if shift := day.Cells[row].Shift; shift != "" {
+					fmt.Fprintf(buf, "<span class=\"shift\">%s</span>", html.EscapeString(shift))
+				}
+				for _, line := range cellLines(day.Cells[row].Lines, maxLines) {
+					prefix := ""
+					if line.Checkbox {
+						prefix = "<span class=\"checkbox\"></span>"
+					}
+					if icon, ok := textIcons[line.Icon]; ok {
+						prefix += "<span class=\"icon\">" + icon + "</span>"
+					}
+					fmt.Fprintf(buf, "<div class=\"line\">%s%s</div>", prefix, html.EscapeString(line.Text))
+				}
+			}
+			buf.WriteString("</td>")
+		}
+		buf.WriteString("</tr>\n")
+	}
+	buf.WriteString("</table>\n</div>\n")
+}
+
+// writeHTMLMiniMonth writes a small overview of a month as an HTML table, like drawMiniMonth
+func writeHTMLMiniMonth(buf *bufio.Writer, cal kal.Calendar, tmpl *Template, r Region, year, week int) error {
+	style := tmpl.style(r, Style{Font: "regular", Size: 8})
+	mondayTime := FirstMondayOfWeek(year, week)
+	first := time.Date(mondayTime.Year(), mondayTime.Month()+time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC)
+	last := first.AddDate(0, 1, -1)
+
+	fmt.Fprintf(buf, "<div class=\"region minimonth\" style=\"%s%s\">\n", regionStyle(r), fontStyle(style))
+	fmt.Fprintf(buf, "<div class=\"title\">%s %d</div>\n<table>\n<tr>", html.EscapeString(GetMonthName(cal, first)), first.Year())
+	for _, dayName := range strings.Fields(kal.TwoLetterDays(cal, true)) {
+		fmt.Fprintf(buf, "<th>%s</th>", html.EscapeString(capitalize(dayName)))
+	}
+	buf.WriteString("</tr>\n<tr>")
+	// Empty cells before the first day of the month, monday first
+	buf.WriteString(strings.Repeat("<td></td>", (int(first.Weekday())+6)%7))
+	err := IterateDays(first, last, func(t time.Time) error {
+		if t.Weekday() == time.Monday && t.Day() != 1 {
+			buf.WriteString("</tr>\n<tr>")
+		}
+		class := ""
+		if isRedDay(cal, t) {
+			class = " class=\"red\""
+		}
+		fmt.Fprintf(buf, "<td%s>%d</td>", class, t.Day())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	buf.WriteString("</tr>\n</table>\n</div>\n")
+	return nil
+}
+
+// writeHTMLNotes writes a labeled area with ruled lines, like drawNotes
+func writeHTMLNotes(buf *bufio.Writer, tmpl *Template, r Region) {
+	style := tmpl.style(r, Style{Font: "bold", Size: 12, LineWidth: 0.5})
+	fmt.Fprintf(buf, "<div class=\"region notes\" style=\"%s\">\n", regionStyle(r))
+	height := r.Height
+	if r.Text != "" {
+		labelHeight := float64(style.Size) * 1.5
+		fmt.Fprintf(buf, "<div class=\"label\" style=\"height: %.2fpt;%s\">%s</div>\n", labelHeight, fontStyle(style), html.EscapeString(r.Text))
+		height -= labelHeight
+	}
+	lines := r.Lines
+	if lines <= 0 {
+		lines = 5
+	}
+	for i := 0; i < lines; i++ {
+		fmt.Fprintf(buf, "<div class=\"line\" style=\"height: %.2fpt; border-bottom-width: %.2fpt;\"></div>\n", height/float64(lines), style.LineWidth)
+	}
+	buf.WriteString("</div>\n")
+}
+
+// GenerateHTML generates a self-contained HTML page for the given year and week, using the default template
+func GenerateHTML(year, week int, names []string, drawing bool) ([]byte, error) {
+	return GenerateHTMLWithOptions(Options{Year: year, Week: week, Names: names, Drawing: drawing})
+}
+
+// GenerateHTMLWithOptions generates a self-contained HTML page, with inline CSS, embedded fonts and
+// the drawing as inline SVG. The page is laid out by the same template regions as the PDF.
+func GenerateHTMLWithOptions(opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, opts); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteHTML writes a self-contained HTML page to the given writer, laid out by the template in the
+// given options, see GenerateHTMLWithOptions. The page is written region by region, as it is made.
+func WriteHTML(w io.Writer, opts Options) error {
+	buf := bufio.NewWriter(w)
+	if err := writeHTML(buf, opts); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// writeHTML writes a self-contained HTML page to the given buffered writer
+func writeHTML(buf *bufio.Writer, opts Options) error {
+	cal, err := NewCalendar()
+	if err != nil {
+		return err
+	}
+	tmpl, err := opts.template()
+	if err != nil {
+		return err
+	}
+	page, err := buildPage(cal, tmpl, opts)
+	if err != nil {
+		return err
+	}
+
+	size := pageSize(tmpl)
+	title := page.Title
+
+	fmt.Fprintf(buf, htmlHead,
+		html.EscapeString(title),
+		base64.StdEncoding.EncodeToString(nunitoRegularData),
+		base64.StdEncoding.EncodeToString(nunitoBoldData),
+		size.W, size.H, size.W, size.H)
+
+	for _, r := range tmpl.Regions {
+		switch r.Type {
+		case RegionTitle:
+			style := tmpl.style(r, Style{Font: "bold", Size: 24})
+			fmt.Fprintf(buf, "<div class=\"region\" style=\"%s%s\">%s</div>\n", regionStyle(r), fontStyle(style), html.EscapeString(title))
+		case RegionDrawing:
+			if opts.Drawing {
+				svg := NewSVGSurface(r.Width, r.Height)
+				if err := DrawLineImage(svg, opts.Year, opts.Week, 0, 0, r.Width, r.Height); err != nil {
+					return err
+				}
+				fmt.Fprintf(buf, "<div class=\"region\" style=\"%s\">\n", regionStyle(r))
+				buf.Write(svg.Element())
+				buf.WriteString("</div>\n")
+			}
+		case RegionWeek:
+			writeHTMLWeek(buf, page.Weeks[r.Week], page.Names, page.Legends, tmpl.style(r, Style{Font: "regular", Size: 12}), r)
+		case RegionNotes:
+			writeHTMLNotes(buf, tmpl, r)
+		case RegionMiniMonth:
+			if err := writeHTMLMiniMonth(buf, cal, tmpl, r, opts.Year, opts.Week); err != nil {
+				return err
+			}
+		case RegionText:
+			style := tmpl.style(r, Style{Font: "regular", Size: 12})
+			text := strings.ReplaceAll(html.EscapeString(r.Text), "\n", "<br>")
+			fmt.Fprintf(buf, "<div class=\"region\" style=\"%s%s\">%s</div>\n", regionStyle(r), fontStyle(style), text)
+		}
+	}
+
+	buf.WriteString(htmlFoot)
+	return nil
+}
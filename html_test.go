@@ -0,0 +1,54 @@
+package kitchencalendar
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateHTML(t *testing.T) {
+	if _, err := NewCalendar(); err != nil {
+		t.Skip(err) // built without a locale
+	}
+	day := time.Date(2025, time.May, 14, 0, 0, 0, 0, time.UTC)
+	opts := Options{
+		Year:    2025,
+		Week:    20,
+		Names:   []string{"Bob & <Alice>", "Judy"},
+		Drawing: true,
+		Events:  []Event{{UID: "party", Summary: `<script>alert("party")</script>`, Start: day, End: day.AddDate(0, 0, 1), AllDay: true, Rows: []string{"Judy"}}},
+	}
+	data, err := GenerateHTMLWithOptions(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(data)
+
+	for _, font := range [][]byte{nunitoRegularData, nunitoBoldData} {
+		if uri := "url(data:font/ttf;base64," + base64.StdEncoding.EncodeToString(font) + ")"; !strings.Contains(s, uri) {
+			t.Error("expected the fonts to be embedded as data URIs")
+		}
+	}
+	// The sundays, at least, are red days
+	if !strings.Contains(s, `<th class="red">`) {
+		t.Error("expected the red days to have the red class")
+	}
+	if !strings.Contains(s, "<svg") {
+		t.Error("expected the drawing as inline SVG")
+	}
+	if !strings.Contains(s, "Bob &amp; &lt;Alice&gt;") || strings.Contains(s, "<Alice>") {
+		t.Error("expected the names to be escaped")
+	}
+	if !strings.Contains(s, "&lt;script&gt;alert(&#34;party&#34;)&lt;/script&gt;") || strings.Contains(s, "<script>alert") {
+		t.Error("expected the event titles to be escaped")
+	}
+
+	opts.Drawing = false
+	if data, err = GenerateHTMLWithOptions(opts); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "<svg") {
+		t.Error("expected no drawing")
+	}
+}
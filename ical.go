@@ -0,0 +1,455 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is an appointment that can be shown in the rows of the people it belongs to
+type Event struct {
+	UID          string
+	Summary      string
+	Start        time.Time
+	End          time.Time // exclusive, the day after the last day for all-day events
+	AllDay       bool
+	Calendar     string // the name of the calendar that the event is from
+	Categories   []string
+	Attendees    []string // the names and e-mail addresses of the attendees
+	RRule        *RRule   // nil if the event does not repeat
+	ExDates      []time.Time
+	RecurrenceID time.Time // the original start of a changed occurrence of a repeating event
+	Rows         []string  // the rows that the event is shown in, instead of using the event rules, if any
+	Task         bool      // a task that is done by the end of the day, shown with a box to tick off
+}
+
+// icsProperty is a content line of an iCalendar file, like DTSTART;TZID=Europe/Oslo:20250517T100000
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// icsUnescape reverses icsEscape
+func icsUnescape(s string) string {
+	var sb strings.Builder
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			sb.WriteRune('\n')
+			escaped = false
+		case escaped:
+			sb.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// splitICSList splits a comma separated list of text values, where commas may be escaped
+func splitICSList(s string) []string {
+	var fields []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case ',':
+			fields = append(fields, icsUnescape(s[start:i]))
+			start = i + 1
+		}
+	}
+	return append(fields, icsUnescape(s[start:]))
+}
+
+// parseICSLine parses an unfolded content line
+func parseICSLine(line string) (icsProperty, error) {
+	p := icsProperty{params: make(map[string]string)}
+	// Find the colon that separates the name and parameters from the value, outside of quotes
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return p, fmt.Errorf("missing colon in %q", line)
+	}
+	p.value = line[colon+1:]
+	fields := strings.Split(line[:colon], ";")
+	p.name = strings.ToUpper(fields[0])
+	for _, field := range fields[1:] {
+		key, value, _ := strings.Cut(field, "=")
+		p.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return p, nil
+}
+
+// floating is the location of times without a time zone. They are placed in the calendar at the
+// same time of day in the time zone of the calendar, see inZone.
+var floating = time.FixedZone("floating", 0)
+
+// inZone returns the given time in the given time zone, or in the local time zone if loc is nil.
+// Times without a time zone keep their time of day.
+func inZone(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.Local
+	}
+	if t.Location() == floating {
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)
+	}
+	return t.In(loc)
+}
+
+// loadLocation returns the time zone with the given TZID, or no time zone if the name
+// is unknown, for instance because it is a Windows time zone name
+func loadLocation(tzid string) *time.Location {
+	if tzid == "" {
+		return floating
+	}
+	if loc, err := time.LoadLocation(tzid); err == nil {
+		return loc
+	}
+	return floating
+}
+
+// parseICSTime parses a DATE or DATE-TIME value. Times that do not end with Z are in the given location.
+// The second return value is true if the value is a date without a time.
+func parseICSTime(value string, loc *time.Location) (time.Time, bool, error) {
+	switch {
+	case len(value) == 8:
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	default:
+		t, err := time.ParseInLocation("20060102T150405", value, loc)
+		return t, false, err
+	}
+}
+
+// propertyTime parses the date or time of a DTSTART, DTEND, EXDATE or RECURRENCE-ID property
+func propertyTime(p icsProperty, value string) (time.Time, bool, error) {
+	t, allDay, err := parseICSTime(value, loadLocation(p.params["TZID"]))
+	if err != nil {
+		return t, allDay, fmt.Errorf("invalid %s: %w", p.name, err)
+	}
+	return t, allDay || p.params["VALUE"] == "DATE", nil
+}
+
+// parseICSDuration parses a DURATION value, like PT1H30M or P1D
+func parseICSDuration(value string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	negative := strings.HasPrefix(value, "-")
+	if negative {
+		s = strings.TrimPrefix(value[1:], "P")
+	}
+	var d time.Duration
+	inTime := false
+	number := ""
+	for _, r := range s {
+		switch {
+		case r == 'T':
+			inTime = true
+		case r >= '0' && r <= '9':
+			number += string(r)
+		default:
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration: %s", value)
+			}
+			number = ""
+			switch {
+			case r == 'W':
+				d += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				d += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				d += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				d += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				d += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration: %s", value)
+			}
+		}
+	}
+	if number != "" {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
+
+// eventBuilder collects the properties of an event, until the end of the event is reached
+type eventBuilder struct {
+	event     Event
+	cancelled bool
+	duration  time.Duration
+	hasEnd    bool
+	rrule     string
+}
+
+// set applies a property to the event. The first return value is false if the property is not
+// a property of events that is used here.
+func (b *eventBuilder) set(p icsProperty) (bool, error) {
+	var err error
+	event := &b.event
+	switch p.name {
+	case "UID":
+		event.UID = p.value
+	case "SUMMARY":
+		event.Summary = icsUnescape(p.value)
+	case "DTSTART":
+		event.Start, event.AllDay, err = propertyTime(p, p.value)
+	case "DTEND":
+		event.End, _, err = propertyTime(p, p.value)
+		b.hasEnd = true
+	case "DURATION":
+		b.duration, err = parseICSDuration(p.value)
+	case "RRULE":
+		b.rrule = p.value
+	case "EXDATE":
+		for _, value := range strings.Split(p.value, ",") {
+			t, _, err := propertyTime(p, value)
+			if err != nil {
+				return true, err
+			}
+			event.ExDates = append(event.ExDates, t)
+		}
+	case "RECURRENCE-ID":
+		event.RecurrenceID, _, err = propertyTime(p, p.value)
+	case "CATEGORIES":
+		for _, category := range splitICSList(p.value) {
+			if category = strings.TrimSpace(category); category != "" {
+				event.Categories = append(event.Categories, category)
+			}
+		}
+	case "ATTENDEE", "ORGANIZER":
+		if cn := p.params["CN"]; cn != "" {
+			event.Attendees = append(event.Attendees, cn)
+		}
+		if email := p.value; len(email) > 7 && strings.EqualFold(email[:7], "mailto:") {
+			event.Attendees = append(event.Attendees, email[7:])
+		}
+	case "STATUS":
+		b.cancelled = strings.EqualFold(p.value, "CANCELLED")
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// finish returns the event, with the end time and the recurrence rule filled in.
+// The second return value is false if the event is cancelled or has no start time.
+func (b *eventBuilder) finish() (Event, bool, error) {
+	event := b.event
+	if b.rrule != "" {
+		var err error
+		if event.RRule, err = ParseRRule(b.rrule, event.Start.Location()); err != nil {
+			return event, false, err
+		}
+	}
+	if !b.hasEnd {
+		switch {
+		case b.duration != 0:
+			event.End = event.Start.Add(b.duration)
+		case event.AllDay:
+			event.End = event.Start.AddDate(0, 0, 1)
+		default:
+			event.End = event.Start
+		}
+	}
+	return event, !b.cancelled && !event.Start.IsZero(), nil
+}
+
+// unfoldICSLines reads the content lines of an iCalendar file, joining lines that are folded.
+// The second return value is the line number where each of the content lines starts.
+func unfoldICSLines(r io.Reader) ([]string, []int, error) {
+	var (
+		lines       []string
+		lineNumbers []int
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+			lineNumbers = append(lineNumbers, n)
+		}
+	}
+	return lines, lineNumbers, scanner.Err()
+}
+
+// ParseICS reads the events of an iCalendar file. Cancelled events are left out.
+func ParseICS(r io.Reader) ([]Event, error) {
+	var (
+		events  []Event
+		calName string
+		builder *eventBuilder
+		// the components that are open, like VCALENDAR, VEVENT and VALARM
+		components []string
+	)
+
+	lines, lineNumbers, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, line := range lines {
+		p, err := parseICSLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumbers[i], err)
+		}
+		switch p.name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(p.value))
+			if strings.EqualFold(p.value, "VEVENT") {
+				builder = &eventBuilder{}
+			}
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(p.value) {
+				return nil, fmt.Errorf("line %d: END:%s without a matching BEGIN", lineNumbers[i], p.value)
+			}
+			components = components[:len(components)-1]
+			if strings.EqualFold(p.value, "VEVENT") {
+				if builder != nil {
+					event, ok, err := builder.finish()
+					if err != nil {
+						return nil, fmt.Errorf("line %d: %w", lineNumbers[i], err)
+					}
+					if ok {
+						events = append(events, event)
+					}
+				}
+				builder = nil
+			}
+			continue
+		}
+
+		if len(components) == 1 && components[0] == "VCALENDAR" && p.name == "X-WR-CALNAME" {
+			calName = icsUnescape(p.value)
+			continue
+		}
+		// Only use the properties of the event itself, not of alarms within the event
+		if builder == nil || len(components) == 0 || components[len(components)-1] != "VEVENT" {
+			continue
+		}
+		if _, err := builder.set(p); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumbers[i], err)
+		}
+	}
+
+	for i := range events {
+		if events[i].Calendar == "" {
+			events[i].Calendar = calName
+		}
+	}
+	return events, nil
+}
+
+// LoadICS reads the events of an iCalendar file. If the file does not name the calendar,
+// the filename without the extension is used as the name of the calendar.
+func LoadICS(filename string) ([]Event, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, err := ParseICS(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	for i := range events {
+		if events[i].Calendar == "" {
+			events[i].Calendar = name
+		}
+	}
+	return events, nil
+}
+
+// ExpandEvents returns the events, and the occurrences of repeating events, that take place
+// from the given time to the given time (inclusive), sorted by their start times.
+// Changed occurrences of repeating events replace the original occurrences.
+func ExpandEvents(events []Event, from, to time.Time) []Event {
+	// Changed occurrences, by UID and original start time
+	changed := make(map[string]bool)
+	for _, e := range events {
+		if !e.RecurrenceID.IsZero() {
+			changed[e.UID+e.RecurrenceID.UTC().Format(time.RFC3339)] = true
+		}
+	}
+	overlaps := func(e Event) bool {
+		end := e.End
+		if !end.After(e.Start) {
+			end = e.Start.Add(time.Nanosecond)
+		}
+		return end.After(from) && !e.Start.After(to)
+	}
+	var expanded []Event
+	for _, e := range events {
+		if e.RRule == nil {
+			if overlaps(e) {
+				expanded = append(expanded, e)
+			}
+			continue
+		}
+		length := e.End.Sub(e.Start)
+		// All-day events last a number of days, also across daylight saving time changes
+		days := int(math.Round(length.Hours() / 24))
+		// Also find occurrences that start before the range, but last into it
+		searchFrom := from.Add(-length)
+	occurrences:
+		for _, start := range e.RRule.Between(e.Start, searchFrom, to) {
+			for _, exdate := range e.ExDates {
+				if exdate.Equal(start) || e.AllDay && exdate.Format("20060102") == start.Format("20060102") {
+					continue occurrences
+				}
+			}
+			if changed[e.UID+start.UTC().Format(time.RFC3339)] {
+				continue
+			}
+			occurrence := e
+			occurrence.RRule = nil
+			occurrence.ExDates = nil
+			occurrence.Start = start
+			if e.AllDay {
+				occurrence.End = start.AddDate(0, 0, days)
+			} else {
+				occurrence.End = start.Add(length)
+			}
+			if overlaps(occurrence) {
+				expanded = append(expanded, occurrence)
+			}
+		}
+	}
+	sort.SliceStable(expanded, func(i, j int) bool {
+		return expanded[i].Start.Before(expanded[j].Start)
+	})
+	return expanded
+}
@@ -0,0 +1,178 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"X-WR-CALNAME:Family\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:dentist\r\n" +
+	"SUMMARY:Dentist\\, check-up\r\n" +
+	"DTSTART:20250513T083000Z\r\n" +
+	"DTEND:20250513T093000Z\r\n" +
+	"ATTENDEE;CN=\"Bob B.\";ROLE=REQ-PARTICIPANT:mailto:bob@example.com\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:football\r\n" +
+	"SUMMARY:Foot\r\n" +
+	" ball\r\n" +
+	"DTSTART:20250505T150000Z\r\n" +
+	"DURATION:PT1H30M\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE\r\n" +
+	"EXDATE:20250507T150000Z\r\n" +
+	"CATEGORIES:Sports,Kids\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"SUMMARY:Reminder\r\n" +
+	"TRIGGER:-PT15M\r\n" +
+	"END:VALARM\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:football\r\n" +
+	"RECURRENCE-ID:20250512T150000Z\r\n" +
+	"SUMMARY:Football match\r\n" +
+	"DTSTART:20250512T160000Z\r\n" +
+	"DTEND:20250512T180000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cabin\r\n" +
+	"SUMMARY:Cabin trip\r\n" +
+	"DTSTART;VALUE=DATE:20250516\r\n" +
+	"DTEND;VALUE=DATE:20250519\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:cancelled\r\n" +
+	"SUMMARY:Cancelled\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"DTSTART:20250514T100000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	events, err := ParseICS(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("expected 4 events, got %d", len(events))
+	}
+	dentist := events[0]
+	if dentist.Summary != "Dentist, check-up" || dentist.Calendar != "Family" {
+		t.Errorf("unexpected event: %+v", dentist)
+	}
+	if len(dentist.Attendees) != 2 || dentist.Attendees[0] != "Bob B." || dentist.Attendees[1] != "bob@example.com" {
+		t.Errorf("unexpected attendees: %q", dentist.Attendees)
+	}
+	football := events[1]
+	if football.Summary != "Football" || football.RRule == nil || len(football.ExDates) != 1 || len(football.Categories) != 2 {
+		t.Errorf("unexpected event: %+v", football)
+	}
+	if football.End.Sub(football.Start) != 90*time.Minute {
+		t.Errorf("expected the duration to be used for the end time, got %v", football.End)
+	}
+	cabin := events[3]
+	if !cabin.AllDay || cabin.End.Sub(cabin.Start) != 72*time.Hour {
+		t.Errorf("unexpected all-day event: %+v", cabin)
+	}
+
+	if _, err := ParseICS(strings.NewReader("BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2025\nEND:VEVENT\nEND:VCALENDAR\n")); err == nil || !strings.HasPrefix(err.Error(), "line 3:") {
+		t.Errorf("expected an error for line 3, got %v", err)
+	}
+	// Unbalanced END lines, which must give an error and not a panic
+	for _, data := range []string{
+		"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VALARM\nEND:VALARM\nSUMMARY:x\n",
+		"END:VEVENT\nSUMMARY:x\n",
+		"BEGIN:VCALENDAR\nEND:VCALENDAR\nEND:VCALENDAR\n",
+	} {
+		if _, err := ParseICS(strings.NewReader(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
+
+func TestExpandEvents(t *testing.T) {
+	events, err := ParseICS(strings.NewReader(testICS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2025, 5, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 5, 14, 23, 59, 0, 0, time.UTC)
+	var summaries []string
+	for _, e := range ExpandEvents(events, from, to) {
+		summaries = append(summaries, e.Start.Format("02 15:04 ")+e.Summary)
+	}
+	// The 7th is excluded, and the occurrence on the 12th has been moved
+	expected := "05 15:00 Football, 12 16:00 Football match, 13 08:30 Dentist, check-up, 14 15:00 Football"
+	if got := strings.Join(summaries, ", "); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestEventRows(t *testing.T) {
+	names := []string{"Bob", "Alice", "Mallory"}
+	e := Event{Calendar: "Family", Categories: []string{"Sports"}, Attendees: []string{"bob"}}
+	rules, err := ParseEventRules("category:sports=Alice, calendar:Family=Mallory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows := eventRows(e, rules, names, ""); len(rows) != 2 || rows[0] != 1 || rows[1] != 2 {
+		t.Errorf("expected the rows of Alice and Mallory, got %v", rows)
+	}
+	// Without matching rules, the attendee with the same name as a row is used
+	if rows := eventRows(e, nil, names, ""); len(rows) != 1 || rows[0] != 0 {
+		t.Errorf("expected the row of Bob, got %v", rows)
+	}
+	if rows := eventRows(e, []EventRule{{Field: "calendar", Value: "family", Row: "*"}}, names, ""); len(rows) != 3 {
+		t.Errorf("expected all rows, got %v", rows)
+	}
+	// Events that do not belong to anyone are shown in the shared row, if there is one
+	names = append(names, "Home")
+	other := Event{Calendar: "Work"}
+	if rows := eventRows(other, rules, names, ""); len(rows) != 0 {
+		t.Errorf("expected no rows, got %v", rows)
+	}
+	if rows := eventRows(other, rules, names, "Home"); len(rows) != 1 || rows[0] != 3 {
+		t.Errorf("expected the shared row, got %v", rows)
+	}
+	// The rows of the event itself are used instead of the rules
+	e.Rows = []string{"Bob"}
+	if rows := eventRows(e, rules, names, "Home"); len(rows) != 1 || rows[0] != 0 {
+		t.Errorf("expected the row of Bob, got %v", rows)
+	}
+	if _, err := ParseEventRules("summary:x=Bob"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestEventLine(t *testing.T) {
+	cabin := Event{
+		Summary: "Cabin trip",
+		AllDay:  true,
+		Start:   time.Date(2025, 5, 16, 0, 0, 0, 0, time.Local),
+		End:     time.Date(2025, 5, 19, 0, 0, 0, 0, time.Local),
+	}
+	for day, expected := range map[int]bool{15: false, 16: true, 18: true, 19: false} {
+		if _, ok := eventLine(cabin, time.Date(2025, 5, day, 0, 0, 0, 0, time.UTC), nil); ok != expected {
+			t.Errorf("the %dth of May: expected %v, got %v", day, expected, ok)
+		}
+	}
+}
+
+func TestEventLineTimeZone(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2025, 5, 7, 0, 0, 0, 0, time.UTC)
+	// Times without a time zone keep their time of day, and other times are shown in the given time zone
+	for _, start := range []time.Time{time.Date(2025, 5, 7, 17, 0, 0, 0, floating), time.Date(2025, 5, 7, 15, 0, 0, 0, time.UTC)} {
+		e := Event{Summary: "Football", Start: start, End: start.Add(time.Hour)}
+		line, ok := eventLine(e, day, oslo)
+		if expected := FormatTime(time.Date(2025, 5, 7, 17, 0, 0, 0, oslo)) + " Football"; !ok || line != expected {
+			t.Errorf("expected %q for %v, got %q", expected, start, line)
+		}
+	}
+}
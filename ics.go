@@ -0,0 +1,178 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xyproto/kal"
+)
+
+// icsProductID identifies this program in the generated iCalendar files
+const icsProductID = "-//xyproto//kitchencalendar//EN"
+
+// icsEscape escapes a text value, as described in RFC 5545, section 3.3.11
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line so that no line is longer than 75 octets, without splitting UTF-8 sequences
+func icsFold(line string) string {
+	const maxOctets = 75
+	var sb strings.Builder
+	octets := 0
+	for _, r := range line {
+		n := utf8.RuneLen(r)
+		if octets+n > maxOctets {
+			sb.WriteString("\r\n ")
+			octets = 1 // the leading space counts
+		}
+		sb.WriteRune(r)
+		octets += n
+	}
+	return sb.String()
+}
+
+// icsUID returns a UID that is the same every time the same event is generated,
+// so that importing the file again does not create duplicate events
+func icsUID(date time.Time, kind, summary string) string {
+	h := fnv.New32a()
+	h.Write([]byte(summary))
+	return fmt.Sprintf("%s-%s-%08x@kitchencalendar", date.Format("20060102"), kind, h.Sum32())
+}
+
+// writeICSEvent writes an all-day VEVENT, stamped with the time the file was generated, in UTC
+func writeICSEvent(buf *bufio.Writer, date, stamp time.Time, kind, summary, category string) {
+	lines := []string{
+		"BEGIN:VEVENT",
+		"UID:" + icsUID(date, kind, summary),
+		"DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"),
+		"DTSTART;VALUE=DATE:" + date.Format("20060102"),
+		"DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"),
+		"SUMMARY:" + icsEscape(summary),
+		"CATEGORIES:" + icsEscape(category),
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+	}
+	for _, line := range lines {
+		buf.WriteString(icsFold(line) + "\r\n")
+	}
+}
+
+// writeCalendarICS writes an iCalendar file to the given writer, one event at a time, with an all-day
+// event for every red day and notable day that the given calendar reports for the given days,
+// stamped with the given time. Sundays that are not also holidays are left out.
+func writeCalendarICS(w io.Writer, cal kal.Calendar, days []time.Time, stamp time.Time) error {
+	buf := bufio.NewWriter(w)
+	header := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + icsProductID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	for _, line := range header {
+		buf.WriteString(line + "\r\n")
+	}
+	for _, t := range days {
+		if name := holidayName(cal, t); name != "" {
+			writeICSEvent(buf, t, stamp, "red", name, "Holiday")
+		}
+		if notable, desc, _ := cal.NotableDay(t); notable && desc != "" {
+			writeICSEvent(buf, t, stamp, "notable", desc, "Notable day")
+		}
+	}
+	buf.WriteString("END:VCALENDAR\r\n")
+	return buf.Flush()
+}
+
+// daysBetween returns the days from the given date to the given date (inclusive)
+func daysBetween(from, to time.Time) []time.Time {
+	var days []time.Time
+	IterateDays(from, to, func(t time.Time) error {
+		days = append(days, t)
+		return nil
+	})
+	return days
+}
+
+// pageDays returns the days that are shown on the given pages, in order and without duplicates,
+// so that pages that overlap or leave weeks out in between are covered exactly
+func pageDays(pages []Options) ([]time.Time, error) {
+	seen := make(map[string]bool)
+	var days []time.Time
+	for _, page := range pages {
+		from, to, err := DateRange(page)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range daysBetween(from, to) {
+			if key := t.Format("2006-01-02"); !seen[key] {
+				seen[key] = true
+				days = append(days, t)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days, nil
+}
+
+// GenerateICS generates an iCalendar (.ics) file with an all-day event for every red day
+// and notable day from the given date to the given date (inclusive), for the current locale
+func GenerateICS(from, to time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteICS(&buf, from, to); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GeneratePagesICS generates an iCalendar (.ics) file with an all-day event for every red day
+// and notable day that is shown on the given pages, for the current locale
+func GeneratePagesICS(pages []Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WritePagesICS(&buf, pages); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteICS writes an iCalendar (.ics) file with the red days and notable days
+// from the given date to the given date (inclusive) to the given writer, one event at a time
+func WriteICS(w io.Writer, from, to time.Time) error {
+	cal, err := NewCalendar()
+	if err != nil {
+		return err
+	}
+	return writeCalendarICS(w, cal, daysBetween(from, to), time.Now())
+}
+
+// WritePagesICS writes an iCalendar (.ics) file with the red days and notable days
+// that are shown on the given pages to the given writer, one event at a time
+func WritePagesICS(w io.Writer, pages []Options) error {
+	days, err := pageDays(pages)
+	if err != nil {
+		return err
+	}
+	cal, err := NewCalendar()
+	if err != nil {
+		return err
+	}
+	return writeCalendarICS(w, cal, days, time.Now())
+}
+
+// DateRange returns the first and the last day that are shown on the page for the given options
+func DateRange(opts Options) (time.Time, time.Time, error) {
+	tmpl, err := opts.template()
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from := FirstMondayOfWeek(opts.Year, opts.Week)
+	return from, from.AddDate(0, 0, 7*tmpl.Weeks()-1), nil
+}
@@ -0,0 +1,81 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestICSEscape(t *testing.T) {
+	if s := icsEscape(`a,b;c\d` + "\n"); s != `a\,b\;c\\d\n` {
+		t.Errorf("unexpected escaped text: %q", s)
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("æ", 50)
+	for _, folded := range strings.Split(icsFold(line), "\r\n") {
+		if len(folded) > 75 {
+			t.Errorf("the folded line is %d octets long: %q", len(folded), folded)
+		}
+	}
+	if unfolded := strings.ReplaceAll(icsFold(line), "\r\n ", ""); unfolded != line {
+		t.Errorf("unfolding gave %q", unfolded)
+	}
+}
+
+func TestCalendarICS(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Week 20 in 2025, with the 17th of May on a saturday and an ordinary sunday
+	from := time.Date(2025, time.May, 12, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.May, 18, 0, 0, 0, 0, time.UTC)
+	stamp := time.Date(2025, time.May, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	var buf bytes.Buffer
+	if err := writeCalendarICS(&buf, cal, daysBetween(from, to), stamp); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if !strings.HasPrefix(s, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(s, "END:VCALENDAR\r\n") {
+		t.Errorf("expected a VCALENDAR, got:\n%s", s)
+	}
+	if n := strings.Count(s, "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("expected 1 event, got %d:\n%s", n, s)
+	}
+	if !strings.Contains(s, "DTSTART;VALUE=DATE:20250517\r\n") || !strings.Contains(s, "DTEND;VALUE=DATE:20250518\r\n") {
+		t.Errorf("expected an all-day event on the 17th of May, got:\n%s", s)
+	}
+	if !strings.Contains(s, "DTSTAMP:20250501T103000Z\r\n") {
+		t.Errorf("expected the time the file was generated, in UTC, got:\n%s", s)
+	}
+	var again bytes.Buffer
+	if err := writeCalendarICS(&again, cal, daysBetween(from, to), stamp); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != s {
+		t.Error("expected the same output, with the same UIDs, every time")
+	}
+}
+
+func TestPageDays(t *testing.T) {
+	// Two two-week pages with two weeks left out in between, and a page that is the same as the first one
+	pages := []Options{{Year: 2025, Week: 24}, {Year: 2025, Week: 20}, {Year: 2025, Week: 20}}
+	days, err := pageDays(pages)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 28 {
+		t.Fatalf("expected 28 days, got %d: %v", len(days), days)
+	}
+	if first, last := days[0].Format("2006-01-02"), days[27].Format("2006-01-02"); first != "2025-05-12" || last != "2025-06-22" {
+		t.Errorf("expected 2025-05-12 to 2025-06-22, got %s to %s", first, last)
+	}
+	if days[14].Format("2006-01-02") != "2025-06-09" {
+		t.Errorf("expected weeks 22 and 23 to be left out, got %s", days[14].Format("2006-01-02"))
+	}
+}
@@ -0,0 +1,254 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+// Page is the contents of a calendar page, computed before anything is drawn.
+// All renderers draw the titles, labels and red days from this structure.
+type Page struct {
+	Year  int      `json:"year"`
+	Week  int      `json:"week"`
+	Title string   `json:"title"`
+	Names []string `json:"names"`
+	Weeks []Week   `json:"weeks"`
+
+	Legends [][]string `json:"legends,omitempty"` // the lines below the name of each row, like the meaning of shift codes
+}
+
+// Week is one week of a page, shown as a table with one column per day and one row per person
+type Week struct {
+	Year   int    `json:"year"`   // the ISO year
+	Number int    `json:"number"` // the ISO week number
+	Label  string `json:"label"`  // the week number, like "Week 20"
+	Period string `json:"period"` // the first and last date of the week
+	Days   []Day  `json:"days"`
+}
+
+// Day is one day of a week
+type Day struct {
+	Time         time.Time `json:"-"`
+	Date         string    `json:"date"`  // the date on the form YYYY-MM-DD
+	Label        string    `json:"label"` // the day name and date, like "Monday 24."
+	Red          bool      `json:"red"`   // a Sunday or a public holiday
+	Notable      bool      `json:"notable"`
+	Holiday      string    `json:"holiday,omitempty"`      // the name of the public holiday
+	NotableDay   string    `json:"notableDay,omitempty"`   // the name of the notable day
+	MonthStart   bool      `json:"monthStart"`             // the first day of a month
+	Notes        []string  `json:"notes,omitempty"`        // short notes below the day label, like holiday names and birthdays
+	Moon         string    `json:"moon,omitempty"`         // the icon of the phase of the moon, like IconFullMoon, if it changes that day
+	Sun          string    `json:"sun,omitempty"`          // the sunrise and sunset, or the day length, like "03:53-22:44"
+	NameDays     []string  `json:"nameDays,omitempty"`     // the names that are celebrated this day
+	NameDayMatch bool      `json:"nameDayMatch,omitempty"` // one of the name days is the name of a row
+	Cells        []Cell    `json:"cells"`
+}
+
+// Cell is the space for one person on one day
+type Cell struct {
+	Name   string `json:"name"`
+	Lines  []Line `json:"lines,omitempty"`
+	Shaded bool   `json:"shaded,omitempty"` // the day is within a period, like a school holiday, for this person
+
+	Responsible bool   `json:"responsible,omitempty"` // it is the turn of this person in a custody pattern
+	Shift       string `json:"shift,omitempty"`       // the shift code of this person, like "N", printed in the corner
+}
+
+// Line is a line of text that is printed in a cell, like an event or a chore
+type Line struct {
+	Text     string `json:"text"`
+	Checkbox bool   `json:"checkbox,omitempty"` // a box that can be ticked off is drawn before the text
+	Icon     string `json:"icon,omitempty"`     // a small icon that is drawn before the text, like IconCake
+}
+
+// The icons that can be drawn in the cells and the day headers
+const (
+	IconCake         = "cake" // a birthday
+	IconNewMoon      = "newmoon"
+	IconFirstQuarter = "firstquarter"
+	IconFullMoon     = "fullmoon"
+	IconLastQuarter  = "lastquarter"
+)
+
+// dayNote is a line of text below the day label
+type dayNote struct {
+	Text string
+	Bold bool
+}
+
+// dayNotes returns the lines of text below the day label, which is the sunrise and sunset and the
+// name days followed by the notes. The name days are bold if one of them is the name of a row.
+func dayNotes(day Day) []dayNote {
+	var notes []dayNote
+	if day.Sun != "" {
+		notes = append(notes, dayNote{Text: day.Sun})
+	}
+	if len(day.NameDays) > 0 {
+		notes = append(notes, dayNote{Text: strings.Join(day.NameDays, ", "), Bold: day.NameDayMatch})
+	}
+	for _, note := range day.Notes {
+		notes = append(notes, dayNote{Text: note})
+	}
+	return notes
+}
+
+// holidayName returns the name of the public holiday at the given date, or an empty string.
+// Sundays that are not also holidays have no name.
+func holidayName(cal kal.Calendar, t time.Time) string {
+	red, desc, _ := cal.RedDay(t)
+	if !red || strings.EqualFold(desc, cal.DayName(t.Weekday())) {
+		return ""
+	}
+	return desc
+}
+
+// addHolidayNotes adds the names of the public holidays to the notes below the day labels
+func addHolidayNotes(w *Week) {
+	for i, day := range w.Days {
+		if day.Holiday != "" {
+			w.Days[i].Notes = append(w.Days[i].Notes, day.Holiday)
+		}
+	}
+}
+
+// buildDay computes the contents of a day, with an empty cell for each of the given names
+func buildDay(cal kal.Calendar, t time.Time, names []string) Day {
+	day := Day{
+		Time:       t,
+		Date:       t.Format("2006-01-02"),
+		Label:      DayAndDate(cal, t),
+		Red:        isRedDay(cal, t),
+		Holiday:    holidayName(cal, t),
+		MonthStart: t.Day() == 1,
+		Cells:      make([]Cell, len(names)),
+	}
+	if notable, desc, _ := cal.NotableDay(t); notable {
+		day.Notable = true
+		day.NotableDay = desc
+	}
+	for i, name := range names {
+		day.Cells[i].Name = name
+	}
+	return day
+}
+
+// buildWeek computes the contents of a week, from monday to sunday
+func buildWeek(cal kal.Calendar, year, week int, names []string) (Week, error) {
+	mondayTime := FirstMondayOfWeek(year, week)
+	isoYear, isoWeek := mondayTime.ISOWeek()
+	w := Week{
+		Year:   isoYear,
+		Number: isoWeek,
+		Label:  WeekString(isoWeek),
+		Period: generateWeekHeaderRight(cal, year, week),
+	}
+	err := IterateDays(mondayTime, FirstSundayAfter(mondayTime), func(t time.Time) error {
+		w.Days = append(w.Days, buildDay(cal, t, names))
+		return nil
+	})
+	return w, err
+}
+
+// buildPage computes the contents of a calendar page, with the weeks of the given template
+func buildPage(cal kal.Calendar, tmpl *Template, opts Options) (*Page, error) {
+	if len(opts.Names) == 0 {
+		return nil, errors.New("the given slice of names is empty")
+	}
+	names := opts.Names
+	if opts.SharedRow != "" {
+		// The shared row is shown below the rows of the people
+		names = append(append([]string{}, opts.Names...), opts.SharedRow)
+	}
+	// The tracker rows are shown at the bottom
+	firstTracker := len(names)
+	if len(opts.Trackers) > 0 {
+		names = append([]string{}, names...)
+		for _, t := range opts.Trackers {
+			names = append(names, t.Name)
+		}
+	}
+	page := &Page{
+		Year:  opts.Year,
+		Week:  opts.Week,
+		Title: generateTitle(cal, opts.Year, opts.Week, tmpl.Weeks()),
+		Names: names,
+
+		Legends: shiftLegends(opts.Shifts, names),
+	}
+	page.Legends = addTrackerLegends(page.Legends, opts.Trackers, firstTracker)
+	for i := 0; i < tmpl.Weeks(); i++ {
+		w, err := buildWeek(cal, opts.Year, opts.Week+i, names)
+		if err != nil {
+			return nil, err
+		}
+		if opts.HolidayNotes {
+			addHolidayNotes(&w)
+		}
+		if opts.MoonPhases {
+			addMoonPhases(&w, opts.TimeZone)
+		}
+		if opts.Sun != SunNone {
+			addSun(&w, opts)
+		}
+		if opts.NameDays != nil {
+			addNameDays(&w, opts.NameDays, opts.Names)
+		}
+		if opts.ClockChanges {
+			addClockChanges(&w, opts.TimeZone)
+		}
+		addCustody(&w, opts.Custody, opts.Names)
+		addShifts(&w, opts.Shifts, opts.Names)
+		addPeriods(&w, opts.Periods, opts.Names)
+		addBirthdays(&w, opts.Birthdays, opts.LeapDay, opts.Names)
+		addChores(cal, &w, opts.Chores, opts.Names)
+		addTrackers(&w, opts.Trackers, firstTracker)
+		// The events are only shown in the rows of the people and the shared row, not in the tracker rows
+		addEvents(&w, opts.Events, opts.EventRules, names[:firstTracker], opts.SharedRow, opts.TimeZone)
+		page.Weeks = append(page.Weeks, w)
+	}
+	if len(opts.Countdowns) > 0 {
+		addCountdowns(page, opts.Countdowns, opts.CountdownMode, opts.CountdownDays)
+	}
+	return page, nil
+}
+
+// BuildPage computes the contents of a calendar page for the current locale,
+// with the weeks of the template in the given options
+func BuildPage(opts Options) (*Page, error) {
+	cal, err := NewCalendar()
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := opts.template()
+	if err != nil {
+		return nil, err
+	}
+	return buildPage(cal, tmpl, opts)
+}
+
+// GenerateJSON generates the contents of a calendar page as indented JSON
+func GenerateJSON(opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, opts); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteJSON writes the contents of a calendar page as indented JSON to the given writer
+func WriteJSON(w io.Writer, opts Options) error {
+	page, err := BuildPage(opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false) // keep "->" in the week periods readable
+	enc.SetIndent("", "  ")
+	return enc.Encode(page)
+}
@@ -0,0 +1,47 @@
+package kitchencalendar
+
+import (
+	"testing"
+
+	"github.com/xyproto/kal"
+)
+
+func TestBuildPage(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Week 52 of 2025 is followed by week 1 of 2026, with the 1st of January on a thursday
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 52, Names: []string{"Bob", "Alice"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Weeks) != 2 {
+		t.Fatalf("expected 2 weeks, got %d", len(page.Weeks))
+	}
+	if w := page.Weeks[1]; w.Year != 2026 || w.Number != 1 {
+		t.Errorf("expected the second week to be week 1 of 2026, got week %d of %d", w.Number, w.Year)
+	}
+	christmas := page.Weeks[0].Days[3]
+	if christmas.Date != "2025-12-25" || !christmas.Red || christmas.Holiday == "" {
+		t.Errorf("expected the 25th of December to be a named red day, got %+v", christmas)
+	}
+	sunday := page.Weeks[0].Days[6]
+	if !sunday.Red || sunday.Holiday != "" {
+		t.Errorf("expected an ordinary sunday to be red, without a holiday name, got %+v", sunday)
+	}
+	newYear := page.Weeks[1].Days[3]
+	if !newYear.MonthStart || !newYear.Red {
+		t.Errorf("expected the 1st of January to start a month and be red, got %+v", newYear)
+	}
+	if len(newYear.Cells) != 2 || newYear.Cells[1].Name != "Alice" {
+		t.Errorf("expected one cell per name, got %+v", newYear.Cells)
+	}
+	if _, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 52}); err == nil {
+		t.Error("expected an error when there are no names")
+	}
+}
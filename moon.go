@@ -0,0 +1,198 @@
+package kitchencalendar
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MoonPhase is one of the four main phases of the moon
+type MoonPhase int
+
+const (
+	NewMoon MoonPhase = iota
+	FirstQuarter
+	FullMoon
+	LastQuarter
+)
+
+// String returns the English name of the phase, like "full moon"
+func (p MoonPhase) String() string {
+	switch p {
+	case NewMoon:
+		return "new moon"
+	case FirstQuarter:
+		return "first quarter"
+	case FullMoon:
+		return "full moon"
+	case LastQuarter:
+		return "last quarter"
+	}
+	return "unknown"
+}
+
+// Icon returns the icon of the phase, like IconFullMoon
+func (p MoonPhase) Icon() string {
+	return [...]string{IconNewMoon, IconFirstQuarter, IconFullMoon, IconLastQuarter}[p]
+}
+
+// MoonPhaseTime is the time of a phase of the moon
+type MoonPhaseTime struct {
+	Phase MoonPhase
+	Time  time.Time // in UTC
+}
+
+// synodicMonth is the average number of days from one new moon to the next
+const synodicMonth = 29.530588861
+
+// sinDeg returns the sine of an angle in degrees
+func sinDeg(degrees float64) float64 {
+	return math.Sin(degrees * math.Pi / 180)
+}
+
+// cosDeg returns the cosine of an angle in degrees
+func cosDeg(degrees float64) float64 {
+	return math.Cos(degrees * math.Pi / 180)
+}
+
+// moonPhaseJDE returns the Julian Ephemeris Day of a phase of the moon, where k is the number
+// of lunations since the new moon of the 6th of January 2000, plus 0, 0.25, 0.5 or 0.75 for the phase.
+// This is the algorithm from chapter 49 of Astronomical Algorithms by Jean Meeus, which is accurate
+// to within a minute or so for the years from 1900 to 2100.
+func moonPhaseJDE(k float64, phase MoonPhase) float64 {
+	T := k / 1236.85
+	T2, T3, T4 := T*T, T*T*T, T*T*T*T
+	jde := 2451550.09766 + synodicMonth*k + 0.00015437*T2 - 0.000000150*T3 + 0.00000000073*T4
+	// The eccentricity of the orbit of the earth
+	E := 1 - 0.002516*T - 0.0000074*T2
+	// The mean anomaly of the sun and the moon, the argument of latitude of the moon
+	// and the longitude of the ascending node of the orbit of the moon
+	M := 2.5534 + 29.10535670*k - 0.0000014*T2 - 0.00000011*T3
+	Mm := 201.5643 + 385.81693528*k + 0.0107582*T2 + 0.00001238*T3 - 0.000000058*T4
+	F := 160.7108 + 390.67050284*k - 0.0016118*T2 - 0.00000227*T3 + 0.000000011*T4
+	Omega := 124.7746 - 1.56375588*k + 0.0020672*T2 + 0.00000215*T3
+
+	var c float64
+	switch phase {
+	case NewMoon, FullMoon:
+		if phase == NewMoon {
+			c = -0.40720*sinDeg(Mm) + 0.17241*E*sinDeg(M) + 0.01608*sinDeg(2*Mm) + 0.01039*sinDeg(2*F) +
+				0.00739*E*sinDeg(Mm-M) - 0.00514*E*sinDeg(Mm+M) + 0.00208*E*E*sinDeg(2*M)
+		} else {
+			c = -0.40614*sinDeg(Mm) + 0.17302*E*sinDeg(M) + 0.01614*sinDeg(2*Mm) + 0.01043*sinDeg(2*F) +
+				0.00734*E*sinDeg(Mm-M) - 0.00515*E*sinDeg(Mm+M) + 0.00209*E*E*sinDeg(2*M)
+		}
+		c += -0.00111*sinDeg(Mm-2*F) - 0.00057*sinDeg(Mm+2*F) + 0.00056*E*sinDeg(2*Mm+M) - 0.00042*sinDeg(3*Mm) +
+			0.00042*E*sinDeg(M+2*F) + 0.00038*E*sinDeg(M-2*F) - 0.00024*E*sinDeg(2*Mm-M) - 0.00017*sinDeg(Omega) -
+			0.00007*sinDeg(Mm+2*M) + 0.00004*sinDeg(2*Mm-2*F) + 0.00004*sinDeg(3*M) + 0.00003*sinDeg(Mm+M-2*F) +
+			0.00003*sinDeg(2*Mm+2*F) - 0.00003*sinDeg(Mm+M+2*F) + 0.00003*sinDeg(Mm-M+2*F) - 0.00002*sinDeg(Mm-M-2*F) -
+			0.00002*sinDeg(3*Mm+M) + 0.00002*sinDeg(4*Mm)
+	case FirstQuarter, LastQuarter:
+		c = -0.62801*sinDeg(Mm) + 0.17172*E*sinDeg(M) - 0.01183*E*sinDeg(Mm+M) + 0.00862*sinDeg(2*Mm) +
+			0.00804*sinDeg(2*F) + 0.00454*E*sinDeg(Mm-M) + 0.00204*E*E*sinDeg(2*M) - 0.00180*sinDeg(Mm-2*F) -
+			0.00070*sinDeg(Mm+2*F) - 0.00040*sinDeg(3*Mm) - 0.00034*E*sinDeg(2*Mm-M) + 0.00032*E*sinDeg(M+2*F) +
+			0.00032*E*sinDeg(M-2*F) - 0.00028*E*E*sinDeg(Mm+2*M) + 0.00027*E*sinDeg(2*Mm+M) - 0.00017*sinDeg(Omega) -
+			0.00005*sinDeg(Mm-M-2*F) + 0.00004*sinDeg(2*Mm+2*F) - 0.00004*sinDeg(Mm+M+2*F) + 0.00004*sinDeg(Mm-2*M) +
+			0.00003*sinDeg(Mm+M-2*F) + 0.00003*sinDeg(3*M) + 0.00002*sinDeg(2*Mm-2*F) + 0.00002*sinDeg(Mm-M+2*F) -
+			0.00002*sinDeg(3*Mm+M)
+		W := 0.00306 - 0.00038*E*cosDeg(M) + 0.00026*cosDeg(Mm) - 0.00002*cosDeg(Mm-M) + 0.00002*cosDeg(Mm+M) + 0.00002*cosDeg(2*F)
+		if phase == FirstQuarter {
+			c += W
+		} else {
+			c -= W
+		}
+	}
+
+	// The corrections from the planets
+	planetary := [][3]float64{
+		{299.77, 0.107408, 0.000325},
+		{251.88, 0.016321, 0.000165},
+		{251.83, 26.651886, 0.000164},
+		{349.42, 36.412478, 0.000126},
+		{84.66, 18.206239, 0.000110},
+		{141.74, 53.303771, 0.000062},
+		{207.14, 2.453732, 0.000060},
+		{154.84, 7.306860, 0.000056},
+		{34.52, 27.261239, 0.000047},
+		{207.19, 0.121824, 0.000042},
+		{291.34, 1.844379, 0.000040},
+		{161.72, 24.198154, 0.000037},
+		{239.56, 25.513099, 0.000035},
+		{331.55, 3.592518, 0.000023},
+	}
+	for i, a := range planetary {
+		angle := a[0] + a[1]*k
+		if i == 0 {
+			angle -= 0.009173 * T2
+		}
+		c += a[2] * sinDeg(angle)
+	}
+	return jde + c
+}
+
+// deltaT returns the approximate difference between Terrestrial Time and Universal Time, in seconds,
+// for the given year, using the polynomials of Espenak and Meeus
+func deltaT(year float64) float64 {
+	switch {
+	case year >= 2005 && year < 2050:
+		t := year - 2000
+		return 62.92 + 0.32217*t + 0.005589*t*t
+	case year >= 1986 && year < 2005:
+		t := year - 2000
+		return 63.86 + 0.3345*t - 0.060374*t*t + 0.0017275*t*t*t + 0.000651814*t*t*t*t + 0.00002373599*t*t*t*t*t
+	case year >= 1961 && year < 1986:
+		t := year - 1975
+		return 45.45 + 1.067*t - t*t/260 - t*t*t/718
+	case year >= 2050 && year < 2150:
+		return -20 + 32*math.Pow((year-1820)/100, 2) - 0.5628*(2150-year)
+	}
+	u := (year - 1820) / 100
+	return -20 + 32*u*u
+}
+
+// julianDayToTime converts a Julian Day, in Universal Time, to a time in UTC
+func julianDayToTime(jd float64) time.Time {
+	const unixEpoch = 2440587.5
+	seconds := (jd - unixEpoch) * 86400
+	return time.Unix(0, 0).UTC().Add(time.Duration(math.Round(seconds)) * time.Second)
+}
+
+// MoonPhases returns the new moons, first quarters, full moons and last quarters of the given year,
+// in UTC, sorted by time. They are computed without any network access.
+func MoonPhases(year int) []MoonPhaseTime {
+	var phases []MoonPhaseTime
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0)
+	// Start with the lunation before the year, so that no phase at the start of the year is missed
+	first := math.Floor((float64(year) - 2000) * 12.3685)
+	for k := first - 1; k <= first+14; k++ {
+		for phase := NewMoon; phase <= LastQuarter; phase++ {
+			jde := moonPhaseJDE(k+float64(phase)/4, phase)
+			t := julianDayToTime(jde - deltaT(float64(year))/86400)
+			if !t.Before(from) && t.Before(to) {
+				phases = append(phases, MoonPhaseTime{Phase: phase, Time: t})
+			}
+		}
+	}
+	sort.Slice(phases, func(i, j int) bool { return phases[i].Time.Before(phases[j].Time) })
+	return phases
+}
+
+// addMoonPhases marks the days of the given week where the moon reaches one of its main phases,
+// in the given time zone, or in the local time zone if loc is nil
+func addMoonPhases(w *Week, loc *time.Location) {
+	if len(w.Days) == 0 {
+		return
+	}
+	phases := make(map[string]MoonPhase)
+	for year := w.Days[0].Time.Year() - 1; year <= w.Days[len(w.Days)-1].Time.Year()+1; year++ {
+		for _, p := range MoonPhases(year) {
+			phases[inZone(p.Time, loc).Format("2006-01-02")] = p.Phase
+		}
+	}
+	for i := range w.Days {
+		if phase, ok := phases[w.Days[i].Date]; ok {
+			w.Days[i].Moon = phase.Icon()
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package kitchencalendar
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestMoonPhaseJDE(t *testing.T) {
+	// The new moon of February 1977, example 49.a in Astronomical Algorithms
+	if jde := moonPhaseJDE(-283, NewMoon); math.Abs(jde-2443192.65118) > 0.00001 {
+		t.Errorf("expected JDE 2443192.65118, got %.5f", jde)
+	}
+}
+
+func TestMoonPhases(t *testing.T) {
+	phases := MoonPhases(2025)
+	if len(phases) < 48 || len(phases) > 50 {
+		t.Fatalf("expected about 49 phases in 2025, got %d", len(phases))
+	}
+	// Published times, in UTC
+	expected := []MoonPhaseTime{
+		{FirstQuarter, time.Date(2025, time.January, 6, 23, 56, 0, 0, time.UTC)},
+		{FullMoon, time.Date(2025, time.March, 14, 6, 55, 0, 0, time.UTC)},
+		{NewMoon, time.Date(2025, time.September, 21, 19, 54, 0, 0, time.UTC)},
+		{LastQuarter, time.Date(2025, time.December, 11, 20, 52, 0, 0, time.UTC)},
+	}
+	for _, e := range expected {
+		found := false
+		for _, p := range phases {
+			if p.Phase == e.Phase && p.Time.Sub(e.Time).Abs() <= 2*time.Minute {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected a %s at %s", e.Phase, e.Time.Format(time.RFC3339))
+		}
+	}
+	for i := 1; i < len(phases); i++ {
+		if phases[i].Phase != (phases[i-1].Phase+1)%4 {
+			t.Errorf("expected the phases in order, got %s after %s", phases[i].Phase, phases[i-1].Phase)
+		}
+	}
+}
+
+func TestAddMoonPhases(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 37, Names: []string{"Bob"}, MoonPhases: true, TimeZone: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Last quarter on the 14th of September and new moon on the 21st
+	if moon := page.Weeks[0].Days[6].Moon; moon != IconLastQuarter {
+		t.Errorf("expected the last quarter on the 14th of September, got %q", moon)
+	}
+	if moon := page.Weeks[1].Days[6].Moon; moon != IconNewMoon {
+		t.Errorf("expected a new moon on the 21st of September, got %q", moon)
+	}
+	if moon := page.Weeks[1].Days[0].Moon; moon != "" {
+		t.Errorf("expected no phase on the 15th of September, got %q", moon)
+	}
+
+	// The last quarter is at 10:33 UTC on the 14th, which is past midnight 14 hours ahead of UTC
+	page, err = buildPage(cal, tmpl, Options{Year: 2025, Week: 37, Names: []string{"Bob"}, MoonPhases: true, TimeZone: time.FixedZone("+14", 14*60*60)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moon := page.Weeks[0].Days[6].Moon; moon != "" {
+		t.Errorf("expected no phase on the 14th of September, 14 hours ahead of UTC, got %q", moon)
+	}
+	if moon := page.Weeks[1].Days[0].Moon; moon != IconLastQuarter {
+		t.Errorf("expected the last quarter on the 15th of September, 14 hours ahead of UTC, got %q", moon)
+	}
+}
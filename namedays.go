@@ -0,0 +1,101 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+//go:embed namedays/*.txt
+var nameDaysFS embed.FS
+
+// NameDays are the names that are celebrated on each day of the year, by "MM-DD"
+type NameDays map[string][]string
+
+// ParseNameDays reads a table of name days, with the day and the names separated by commas on each line:
+//
+//	01-02 Dagfinn, Dagfrid
+//	01-03 Alfred, Alf
+//
+// Days without names can be left out. Empty lines and lines starting with # are skipped.
+func ParseNameDays(r io.Reader) (NameDays, error) {
+	nameDays := make(NameDays)
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		day, names, _ := strings.Cut(line, " ")
+		if _, err := time.Parse("2006-01-02", "2000-"+day); err != nil {
+			return nil, fmt.Errorf("line %d: invalid day, expected MM-DD: %s", lineNumber, day)
+		}
+		for _, name := range strings.Split(names, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				nameDays[day] = append(nameDays[day], name)
+			}
+		}
+		if len(nameDays[day]) == 0 {
+			return nil, fmt.Errorf("line %d: missing names: %s", lineNumber, line)
+		}
+	}
+	return nameDays, scanner.Err()
+}
+
+// LoadNameDays reads a table of name days from the given file, see ParseNameDays
+func LoadNameDays(filename string) (NameDays, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	nameDays, err := ParseNameDays(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return nameDays, nil
+}
+
+// BuiltinNameDays returns one of the built-in tables of name days, for example "nb_NO"
+func BuiltinNameDays(locale string) (NameDays, error) {
+	f, err := nameDaysFS.Open("namedays/" + locale + ".txt")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in name days for %q", locale)
+	}
+	defer f.Close()
+	return ParseNameDays(f)
+}
+
+// Names returns the names that are celebrated on the given day
+func (n NameDays) Names(t time.Time) []string {
+	return n[t.Format("01-02")]
+}
+
+// addNameDays adds the name days to the days of the given week, and marks the days where one of
+// the names is the first name of one of the rows. Those names are moved first, so that they are
+// not cut off in narrow columns.
+func addNameDays(w *Week, nameDays NameDays, names []string) {
+	var firstNames []string
+	for _, name := range names {
+		if fields := strings.Fields(name); len(fields) > 0 {
+			firstNames = append(firstNames, fields[0])
+		}
+	}
+	for i := range w.Days {
+		day := &w.Days[i]
+		var matching, other []string
+		for _, name := range nameDays.Names(day.Time) {
+			if anyEqualFold(firstNames, name) {
+				matching = append(matching, name)
+			} else {
+				other = append(other, name)
+			}
+		}
+		day.NameDays = append(matching, other...)
+		day.NameDayMatch = len(matching) > 0
+	}
+}
@@ -0,0 +1,74 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParseNameDays(t *testing.T) {
+	nameDays, err := ParseNameDays(strings.NewReader("# Name days\n01-02 Dagfinn, Dagfrid\n02-29 Leap\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := nameDays.Names(time.Date(2025, time.January, 2, 0, 0, 0, 0, time.UTC)); len(names) != 2 || names[1] != "Dagfrid" {
+		t.Errorf("unexpected names on the 2nd of January: %v", names)
+	}
+	if names := nameDays.Names(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)); len(names) != 1 {
+		t.Errorf("expected a name on the 29th of February, got %v", names)
+	}
+	for _, s := range []string{"13-01 Nobody", "01-32 Nobody", "2025-01-02 Dagfinn", "01-02", "01-02 ,"} {
+		if _, err := ParseNameDays(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestBuiltinNameDays(t *testing.T) {
+	nameDays, err := BuiltinNameDays("nb_NO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every day has names, except New Year's Day, the 29th of February and Christmas Day
+	if len(nameDays) != 363 {
+		t.Errorf("expected name days for 363 days, got %d", len(nameDays))
+	}
+	if names := nameDays.Names(time.Date(2025, time.September, 29, 0, 0, 0, 0, time.UTC)); !anyEqualFold(names, "Mikkel") {
+		t.Errorf("expected Mikkel on the 29th of September, got %v", names)
+	}
+	if _, err := BuiltinNameDays("xx_XX"); err == nil {
+		t.Error("expected an error for a missing table")
+	}
+}
+
+func TestAddNameDays(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nameDays, err := ParseNameDays(strings.NewReader("07-19 Gerhard, Gjert\n07-20 Margareta, Margit, Marit\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 29, Names: []string{"Bob", "Marit H."}, NameDays: nameDays})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saturday, sunday := page.Weeks[0].Days[5], page.Weeks[0].Days[6]
+	if saturday.NameDayMatch || len(saturday.NameDays) != 2 {
+		t.Errorf("expected two names that do not match a row on the 19th of July, got %+v", saturday)
+	}
+	// The matching name is moved first, and the name days are bold
+	if !sunday.NameDayMatch || strings.Join(sunday.NameDays, ", ") != "Marit, Margareta, Margit" {
+		t.Errorf("expected the name day of Marit on the 20th of July, got %+v", sunday)
+	}
+	if notes := dayNotes(sunday); len(notes) == 0 || !notes[0].Bold || notes[0].Text != "Marit, Margareta, Margit" {
+		t.Errorf("expected the name days as the first note, in bold, got %+v", notes)
+	}
+}
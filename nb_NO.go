@@ -38,6 +38,57 @@ func DayAndDate(cal kal.Calendar, t time.Time) string {
 	return fmt.Sprintf("%s %d.", dayName, date)
 }
 
+// FormatTime takes a time.Time and returns the time of day as a string on the format "14:30"
+func FormatTime(t time.Time) string {
+	return t.Format("15:04")
+}
+
+// FormatAge returns an age in years as a string on the form "12 år"
+func FormatAge(years int) string {
+	return fmt.Sprintf("%d år", years)
+}
+
+// FormatDayLength returns the time from sunrise to sunset as a string on the form "18t 51m"
+func FormatDayLength(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dt %dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// FormatClockChange returns how much the clocks are moved as a string on the form "Klokka +1t"
+func FormatClockChange(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "−", -d
+	}
+	if d%time.Hour != 0 {
+		return fmt.Sprintf("Klokka %s%d min", sign, int(d.Minutes()))
+	}
+	return fmt.Sprintf("Klokka %s%dt", sign, int(d.Hours()))
+}
+
+// FormatCountdown returns the number of days left to a day as a string on the form "23 dager til julaften"
+func FormatCountdown(days int, name string) string {
+	if days == 1 {
+		return "1 dag til " + name
+	}
+	return fmt.Sprintf("%d dager til %s", days, name)
+}
+
+// DateLayouts returns the layouts of dates written the local way, like "17.05.2025"
+func DateLayouts() []string {
+	return []string{"02.01.2006", "2.1.2006"}
+}
+
+// PolarDayString is shown instead of the sunrise and sunset when the sun does not set
+func PolarDayString() string {
+	return "Midnattssol"
+}
+
+// PolarNightString is shown instead of the sunrise and sunset when the sun does not rise
+func PolarNightString() string {
+	return "Mørketid"
+}
+
 // NewCalendar returns a new struct that satisfies the kal.Calendar interface
 func NewCalendar() (kal.Calendar, error) {
 	calendar, err := kal.NewCalendar("nb_NO", true)
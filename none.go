@@ -14,4 +14,12 @@ const msg = "select a locale when building, for example: go build -tags nb_NO"
 func FormatDate(cal kal.Calendar, date time.Time) string { return msg }
 func WeekString(week int) string                         { return msg }
 func DayAndDate(cal kal.Calendar, t time.Time) string    { return msg }
+func FormatTime(t time.Time) string                      { return msg }
+func FormatAge(years int) string                         { return msg }
+func FormatDayLength(d time.Duration) string             { return msg }
+func FormatClockChange(d time.Duration) string           { return msg }
+func FormatCountdown(days int, name string) string       { return msg }
+func DateLayouts() []string                              { return nil }
+func PolarDayString() string                             { return msg }
+func PolarNightString() string                           { return msg }
 func NewCalendar() (kal.Calendar, error)                 { return nil, errors.New(msg) }
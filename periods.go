@@ -0,0 +1,105 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Period is a named range of days, like a school holiday or a trip, that is shaded in the calendar
+type Period struct {
+	Name     string
+	From, To time.Time // the first and last day (inclusive), at midnight UTC
+	Rows     []string  // the rows that are shaded, or all rows if empty
+}
+
+// ParsePeriods reads named ranges of days, one on each line, with the first and last day and then
+// the name. The name can be followed by | and a comma separated list of the rows that are shaded,
+// if the period is not for everyone. For example:
+//
+//	2025-02-24 2025-03-02 Vinterferie
+//	2025-07-07 2025-07-11 Summer camp | Alice
+//
+// Empty lines and lines starting with # are skipped.
+func ParsePeriods(r io.Reader) ([]Period, error) {
+	var periods []Period
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("line %d: expected the first day, the last day and a name: %s", lineNumber, line)
+		}
+		from, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date, expected YYYY-MM-DD: %s", lineNumber, fields[0])
+		}
+		to, err := time.Parse("2006-01-02", fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date, expected YYYY-MM-DD: %s", lineNumber, fields[1])
+		}
+		if to.Before(from) {
+			return nil, fmt.Errorf("line %d: the last day is before the first day", lineNumber)
+		}
+		name, rows, _ := strings.Cut(fields[2], "|")
+		p := Period{Name: strings.TrimSpace(name), From: from, To: to}
+		if p.Name == "" {
+			return nil, fmt.Errorf("line %d: missing name: %s", lineNumber, line)
+		}
+		for _, row := range strings.Split(rows, ",") {
+			if row = strings.TrimSpace(row); row != "" {
+				p.Rows = append(p.Rows, row)
+			}
+		}
+		periods = append(periods, p)
+	}
+	return periods, scanner.Err()
+}
+
+// LoadPeriods reads named ranges of days from the given file, see ParsePeriods
+func LoadPeriods(filename string) ([]Period, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	periods, err := ParsePeriods(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return periods, nil
+}
+
+// addPeriods shades the cells of the days within each period. The name of the period is shown on
+// the first day of the period, and on the first day of each week that the period continues into,
+// below the day label if the period is for everyone, or else in the shaded cells.
+func addPeriods(w *Week, periods []Period, names []string) {
+	for _, p := range periods {
+		from, to := dateOf(p.From), dateOf(p.To)
+		for i := range w.Days {
+			day := &w.Days[i]
+			if day.Time.Before(from) || day.Time.After(to) {
+				continue
+			}
+			label := i == 0 || day.Time.Equal(from)
+			if len(p.Rows) == 0 && label {
+				day.Notes = append(day.Notes, p.Name)
+			}
+			for row, name := range names {
+				if len(p.Rows) > 0 && !anyEqualFold(p.Rows, strings.TrimSpace(name)) {
+					continue
+				}
+				day.Cells[row].Shaded = true
+				if len(p.Rows) > 0 && label {
+					day.Cells[row].Lines = append(day.Cells[row].Lines, Line{Text: p.Name})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xyproto/kal"
+)
+
+func TestParsePeriods(t *testing.T) {
+	periods, err := ParsePeriods(strings.NewReader("# School\n2025-02-24 2025-03-02 Vinterferie\n2025-07-07 2025-07-11 Summer camp | Alice, Bob\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(periods) != 2 {
+		t.Fatalf("expected 2 periods, got %+v", periods)
+	}
+	if p := periods[0]; p.Name != "Vinterferie" || p.From.Format("2006-01-02") != "2025-02-24" || p.To.Format("2006-01-02") != "2025-03-02" || len(p.Rows) != 0 {
+		t.Errorf("unexpected period: %+v", p)
+	}
+	if p := periods[1]; p.Name != "Summer camp" || len(p.Rows) != 2 || p.Rows[1] != "Bob" {
+		t.Errorf("unexpected period: %+v", p)
+	}
+	for _, s := range []string{"2025-02-24 Vinterferie", "2025-03-02 2025-02-24 Backwards", "24.02.2025 02.03.2025 Vinterferie", "2025-02-24 2025-03-02  | Alice"} {
+		if _, err := ParsePeriods(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestAddPeriods(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	periods, err := ParsePeriods(strings.NewReader("2025-05-09 2025-05-13 School trip | Alice\n2025-05-15 2025-05-20 Whitsun\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 19, Names: []string{"Bob", "Alice"}, Periods: periods})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the cells of Alice are shaded on the school trip, with the name on the first day of each week
+	friday := page.Weeks[0].Days[4]
+	if friday.Cells[0].Shaded || !friday.Cells[1].Shaded || len(friday.Cells[1].Lines) != 1 || friday.Cells[1].Lines[0].Text != "School trip" {
+		t.Errorf("expected the school trip to start in the row of Alice, got %+v", friday.Cells)
+	}
+	if saturday := page.Weeks[0].Days[5]; !saturday.Cells[1].Shaded || len(saturday.Cells[1].Lines) != 0 {
+		t.Errorf("expected the school trip to be shaded, without a name, got %+v", saturday.Cells)
+	}
+	if monday := page.Weeks[1].Days[0]; !monday.Cells[1].Shaded || len(monday.Cells[1].Lines) != 1 {
+		t.Errorf("expected the name of the school trip at the start of the next week, got %+v", monday.Cells)
+	}
+	if wednesday := page.Weeks[1].Days[2]; wednesday.Cells[1].Shaded {
+		t.Error("expected the school trip to end on the 13th of May")
+	}
+	// Periods for everyone shade all the cells, with the name below the day label
+	thursday := page.Weeks[1].Days[3]
+	if !thursday.Cells[0].Shaded || !thursday.Cells[1].Shaded || len(thursday.Notes) != 1 || thursday.Notes[0] != "Whitsun" {
+		t.Errorf("expected the whole day to be shaded, with a note, got %+v", thursday)
+	}
+	if saturday := page.Weeks[1].Days[5]; !saturday.Cells[0].Shaded || len(saturday.Notes) != 0 {
+		t.Errorf("expected the whole day to be shaded, without the name of the period again, got %+v", saturday)
+	}
+}
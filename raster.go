@@ -0,0 +1,363 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/fogleman/gg"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+)
+
+// defaultDPI is the resolution that is used when rendering images, if no size or DPI is given
+const defaultDPI = 150.0
+
+// RasterOptions are the settings for rendering a calendar page to an image
+type RasterOptions struct {
+	Width      int     // the image width in pixels, derived from Height or DPI if 0
+	Height     int     // the image height in pixels, derived from Width or DPI if 0
+	DPI        float64 // the resolution, used when neither Width nor Height is given
+	Grayscale  bool    // convert the image to grayscale
+	Dither     bool    // dither when reducing the shades of gray, to 1-bit black and white if GrayLevels is 0
+	GrayLevels int     // reduce the image to this many shades of gray (2 is 1-bit, 16 is 4-bit)
+	Rotate     int     // rotate the image clockwise by 90, 180 or 270 degrees, after laying out the page
+}
+
+// RasterSurface draws onto an image, scaling from points to pixels
+type RasterSurface struct {
+	dc          *gg.Context
+	scale       float64
+	offsetX     float64
+	offsetY     float64
+	fonts       map[string]*truetype.Font
+	faces       map[string]map[int]font.Face
+	fontName    string
+	fontSize    int
+	lineWidth   float64
+	strokeColor color.Color
+	fillColor   color.Color
+	textColor   color.Color
+	pathLen     int // the number of connected lines that are not yet stroked
+	pathX       float64
+	pathY       float64
+	lastX       float64 // the last point that was added to the path, in pixels
+	lastY       float64
+	pending     bool // the path ends at (pathX, pathY), which is too close to (lastX, lastY) to be added yet
+}
+
+// minSegment is the shortest line segment that is added to a path, in pixels.
+// Shorter segments are merged, since the rasterizer drops segments that are much shorter than a pixel.
+const minSegment = 0.5
+
+// NewRasterSurface returns a new white image of the given size in pixels.
+// Points are multiplied with scale and offset by (offsetX, offsetY) pixels.
+func NewRasterSurface(width, height int, scale, offsetX, offsetY float64) (*RasterSurface, error) {
+	regularFont, err := truetype.Parse(nunitoRegularData)
+	if err != nil {
+		return nil, err
+	}
+	boldFont, err := truetype.Parse(nunitoBoldData)
+	if err != nil {
+		return nil, err
+	}
+	dc := gg.NewContext(width, height)
+	dc.SetColor(color.White)
+	dc.Clear()
+	return &RasterSurface{
+		dc:          dc,
+		scale:       scale,
+		offsetX:     offsetX,
+		offsetY:     offsetY,
+		fonts:       map[string]*truetype.Font{"regular": regularFont, "bold": boldFont},
+		faces:       map[string]map[int]font.Face{"regular": {}, "bold": {}},
+		fontName:    "regular",
+		fontSize:    12,
+		lineWidth:   1.0,
+		strokeColor: color.Black,
+		fillColor:   color.Black,
+		textColor:   color.Black,
+	}, nil
+}
+
+// px converts a coordinate in points to pixels
+func (s *RasterSurface) px(x, y float64) (float64, float64) {
+	return s.offsetX + x*s.scale, s.offsetY + y*s.scale
+}
+
+// SetFont selects the font ("regular" or "bold") and font size
+func (s *RasterSurface) SetFont(fontName string, fontSize int) error {
+	if _, ok := s.fonts[fontName]; !ok {
+		return errors.New("unknown font: " + fontName)
+	}
+	s.fontName = fontName
+	s.fontSize = fontSize
+	return nil
+}
+
+// SetLineWidth sets the width of the lines that are drawn
+func (s *RasterSurface) SetLineWidth(width float64) {
+	s.strokePath()
+	s.lineWidth = width
+}
+
+// SetStrokeColor sets the color of the lines that are drawn
+func (s *RasterSurface) SetStrokeColor(r, g, b uint8) {
+	s.strokePath()
+	s.strokeColor = color.RGBA{r, g, b, 0xff}
+}
+
+// SetFillColor sets the color of the rectangles that are drawn
+func (s *RasterSurface) SetFillColor(r, g, b uint8) {
+	s.fillColor = color.RGBA{r, g, b, 0xff}
+}
+
+// SetTextColor sets the color of the text that is drawn
+func (s *RasterSurface) SetTextColor(r, g, b uint8) {
+	s.textColor = color.RGBA{r, g, b, 0xff}
+}
+
+// Line draws a line from (x1, y1) to (x2, y2).
+// Lines that continue where the previous line ended are stroked together, as a single path.
+func (s *RasterSurface) Line(x1, y1, x2, y2 float64) {
+	if s.pathLen == 0 || x1 != s.pathX || y1 != s.pathY {
+		s.strokePath()
+		s.lastX, s.lastY = s.px(x1, y1)
+		s.dc.MoveTo(s.lastX, s.lastY)
+	}
+	s.pathX, s.pathY = x2, y2
+	s.pathLen++
+	px, py := s.px(x2, y2)
+	if math.Hypot(px-s.lastX, py-s.lastY) < minSegment {
+		s.pending = true
+		return
+	}
+	s.dc.LineTo(px, py)
+	s.lastX, s.lastY = px, py
+	s.pending = false
+}
+
+// strokePath draws the collected lines
+func (s *RasterSurface) strokePath() {
+	if s.pathLen == 0 {
+		return
+	}
+	if s.pending {
+		s.dc.LineTo(s.px(s.pathX, s.pathY))
+		s.pending = false
+	}
+	s.dc.SetColor(s.strokeColor)
+	s.dc.SetLineWidth(math.Max(s.lineWidth*s.scale, 1.0))
+	s.dc.Stroke()
+	s.pathLen = 0
+}
+
+// FillRect draws a filled rectangle
+func (s *RasterSurface) FillRect(x, y, width, height float64) {
+	s.strokePath()
+	px, py := s.px(x, y)
+	s.dc.DrawRectangle(px, py, width*s.scale, height*s.scale)
+	s.dc.SetColor(s.fillColor)
+	s.dc.Fill()
+}
+
+// Text draws a line of text, where y is the top of the text
+func (s *RasterSurface) Text(x, y float64, text string) error {
+	s.strokePath()
+	face, ok := s.faces[s.fontName][s.fontSize]
+	if !ok {
+		face = truetype.NewFace(s.fonts[s.fontName], &truetype.Options{
+			Size:    float64(s.fontSize) * s.scale,
+			Hinting: font.HintingFull,
+		})
+		s.faces[s.fontName][s.fontSize] = face
+	}
+	s.dc.SetFontFace(face)
+	s.dc.SetColor(s.textColor)
+	px, py := s.px(x, y+float64(s.fontSize)*fontAscent)
+	s.dc.DrawString(text, px, py)
+	return nil
+}
+
+// Image returns the image that has been drawn so far
+func (s *RasterSurface) Image() image.Image {
+	s.strokePath()
+	return s.dc.Image()
+}
+
+// imageSize returns the image size in pixels, the scale from points to pixels and the offset
+// that centers a page of the given size in points
+func (ropts RasterOptions) imageSize(pageWidth, pageHeight float64) (width, height int, scale, offsetX, offsetY float64) {
+	switch {
+	case ropts.Width > 0 && ropts.Height > 0:
+		scale = math.Min(float64(ropts.Width)/pageWidth, float64(ropts.Height)/pageHeight)
+		width, height = ropts.Width, ropts.Height
+	case ropts.Width > 0:
+		scale = float64(ropts.Width) / pageWidth
+		width, height = ropts.Width, int(math.Round(pageHeight*scale))
+	case ropts.Height > 0:
+		scale = float64(ropts.Height) / pageHeight
+		width, height = int(math.Round(pageWidth*scale)), ropts.Height
+	default:
+		dpi := ropts.DPI
+		if dpi <= 0 {
+			dpi = defaultDPI
+		}
+		scale = dpi / 72.0
+		width, height = int(math.Round(pageWidth*scale)), int(math.Round(pageHeight*scale))
+	}
+	offsetX = (float64(width) - pageWidth*scale) / 2
+	offsetY = (float64(height) - pageHeight*scale) / 2
+	return
+}
+
+// grayPalette returns a palette with the given number of evenly spaced shades of gray, from black to white
+func grayPalette(levels int) color.Palette {
+	palette := make(color.Palette, levels)
+	for i := range palette {
+		palette[i] = color.Gray{uint8(i * 255 / (levels - 1))}
+	}
+	return palette
+}
+
+// convertImage converts the image to grayscale or to a few shades of gray, if requested.
+// Without dithering, each pixel gets the closest shade of gray, which keeps thin lines crisp.
+func convertImage(img image.Image, ropts RasterOptions) image.Image {
+	bounds := img.Bounds()
+	levels := ropts.GrayLevels
+	if levels == 0 && ropts.Dither {
+		levels = 2
+	}
+	switch {
+	case levels >= 2:
+		paletted := image.NewPaletted(bounds, grayPalette(min(levels, 256)))
+		if ropts.Dither {
+			draw.FloydSteinberg.Draw(paletted, bounds, img, bounds.Min)
+		} else {
+			draw.Draw(paletted, bounds, img, bounds.Min, draw.Src)
+		}
+		return paletted
+	case ropts.Grayscale:
+		gray := image.NewGray(bounds)
+		draw.Draw(gray, bounds, img, bounds.Min, draw.Src)
+		return gray
+	}
+	return img
+}
+
+// rotateImage rotates the image clockwise by 90, 180 or 270 degrees
+func rotateImage(img image.Image, degrees int) (image.Image, error) {
+	degrees = ((degrees % 360) + 360) % 360
+	if degrees == 0 {
+		return img, nil
+	}
+	if degrees%90 != 0 {
+		return nil, fmt.Errorf("can only rotate by a multiple of 90 degrees, not %d", degrees)
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	var rotated *image.RGBA
+	if degrees == 180 {
+		rotated = image.NewRGBA(image.Rect(0, 0, w, h))
+	} else {
+		rotated = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			switch degrees {
+			case 90:
+				rotated.Set(h-1-y, x, c)
+			case 180:
+				rotated.Set(w-1-x, h-1-y, c)
+			case 270:
+				rotated.Set(y, w-1-x, c)
+			}
+		}
+	}
+	return rotated, nil
+}
+
+// layoutSize returns the size in pixels that the page is laid out with, before rotating, and how it
+// is scaled and placed. When rotating by 90 or 270 degrees, the width and height are swapped.
+func layoutSize(tmpl *Template, ropts RasterOptions) (width, height int, scale, offsetX, offsetY float64, err error) {
+	layout := ropts
+	if ropts.Rotate%180 != 0 {
+		layout.Width, layout.Height = ropts.Height, ropts.Width
+	}
+	size := pageSize(tmpl)
+	width, height, scale, offsetX, offsetY = layout.imageSize(size.W, size.H)
+	if width <= 0 || height <= 0 {
+		return 0, 0, 0, 0, 0, errors.New("the image size must be positive")
+	}
+	return width, height, scale, offsetX, offsetY, nil
+}
+
+// ImageSize returns the width and height in pixels of the image that RenderImage would make,
+// without drawing it, for checking the size before rendering
+func ImageSize(opts Options, ropts RasterOptions) (int, int, error) {
+	tmpl, err := opts.template()
+	if err != nil {
+		return 0, 0, err
+	}
+	width, height, _, _, _, err := layoutSize(tmpl, ropts)
+	if err != nil {
+		return 0, 0, err
+	}
+	if ropts.Rotate%180 != 0 {
+		return height, width, nil
+	}
+	return width, height, nil
+}
+
+// RenderImage renders a calendar page to an image, laid out by the template in the given options
+func RenderImage(opts Options, ropts RasterOptions) (image.Image, error) {
+	cal, err := NewCalendar()
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := opts.template()
+	if err != nil {
+		return nil, err
+	}
+	width, height, scale, offsetX, offsetY, err := layoutSize(tmpl, ropts)
+	if err != nil {
+		return nil, err
+	}
+	surface, err := NewRasterSurface(width, height, scale, offsetX, offsetY)
+	if err != nil {
+		return nil, err
+	}
+	if err := drawRegions(surface, cal, tmpl, opts); err != nil {
+		return nil, err
+	}
+	img, err := rotateImage(surface.Image(), ropts.Rotate)
+	if err != nil {
+		return nil, err
+	}
+	return convertImage(img, ropts), nil
+}
+
+// GeneratePNG renders a calendar page to a PNG image, laid out by the template in the given options
+func GeneratePNG(opts Options, ropts RasterOptions) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, opts, ropts); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WritePNG renders a calendar page to a PNG image and writes it to the given writer
+func WritePNG(w io.Writer, opts Options, ropts RasterOptions) error {
+	img, err := RenderImage(opts, ropts)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
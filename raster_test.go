@@ -0,0 +1,111 @@
+package kitchencalendar
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageSize(t *testing.T) {
+	tests := []struct {
+		ropts         RasterOptions
+		width, height int
+	}{
+		{RasterOptions{DPI: 72}, 595, 842},
+		{RasterOptions{DPI: 144}, 1190, 1684},
+		{RasterOptions{Width: 1190}, 1190, 1684},
+		{RasterOptions{Height: 421}, 298, 421},
+		{RasterOptions{Width: 800, Height: 480}, 800, 480},
+	}
+	for _, tt := range tests {
+		width, height, _, offsetX, offsetY := tt.ropts.imageSize(595, 842)
+		if width != tt.width || height != tt.height {
+			t.Errorf("imageSize(%+v) = %dx%d, want %dx%d", tt.ropts, width, height, tt.width, tt.height)
+		}
+		if offsetX < 0 || offsetY < 0 {
+			t.Errorf("imageSize(%+v) has a negative offset: (%f, %f)", tt.ropts, offsetX, offsetY)
+		}
+	}
+}
+
+func TestImageSizeMatchesRenderImage(t *testing.T) {
+	if _, err := NewCalendar(); err != nil {
+		t.Skip(err) // built without a locale
+	}
+	for _, ropts := range []RasterOptions{{Width: 300}, {Width: 200, Height: 120, Rotate: 90}, {DPI: 20, Rotate: 270}} {
+		width, height, err := ImageSize(Options{Year: 2025, Week: 20, Names: []string{"Alice", "Bob"}}, ropts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		img, err := RenderImage(Options{Year: 2025, Week: 20, Names: []string{"Alice", "Bob"}}, ropts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := img.Bounds(); b.Dx() != width || b.Dy() != height {
+			t.Errorf("ImageSize(%+v) = %dx%d, but the rendered image is %dx%d", ropts, width, height, b.Dx(), b.Dy())
+		}
+	}
+}
+
+func TestConvertImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	img.Set(1, 1, color.Black)
+
+	dithered, ok := convertImage(img, RasterOptions{Dither: true}).(*image.Paletted)
+	if !ok {
+		t.Fatal("expected a paletted image when dithering")
+	}
+	if len(dithered.Palette) != 2 {
+		t.Errorf("expected a palette with 2 colors, got %d", len(dithered.Palette))
+	}
+	if _, ok := convertImage(img, RasterOptions{Grayscale: true}).(*image.Gray); !ok {
+		t.Error("expected a grayscale image")
+	}
+}
+
+func TestRotateImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, color.Black)
+
+	tests := []struct {
+		degrees       int
+		width, height int
+		x, y          int // where the black upper left pixel ends up
+	}{
+		{0, 3, 2, 0, 0},
+		{90, 2, 3, 1, 0},
+		{180, 3, 2, 2, 1},
+		{270, 2, 3, 0, 2},
+		{-90, 2, 3, 0, 2},
+	}
+	for _, tt := range tests {
+		rotated, err := rotateImage(img, tt.degrees)
+		if err != nil {
+			t.Fatalf("rotateImage(%d) returned an error: %v", tt.degrees, err)
+		}
+		b := rotated.Bounds()
+		if b.Dx() != tt.width || b.Dy() != tt.height {
+			t.Errorf("rotateImage(%d) has size %dx%d, want %dx%d", tt.degrees, b.Dx(), b.Dy(), tt.width, tt.height)
+		}
+		if r, _, _, _ := rotated.At(tt.x, tt.y).RGBA(); r != 0 {
+			t.Errorf("rotateImage(%d) should have a black pixel at (%d, %d)", tt.degrees, tt.x, tt.y)
+		}
+	}
+	if _, err := rotateImage(img, 45); err == nil {
+		t.Error("expected an error when rotating by 45 degrees")
+	}
+}
+
+func TestDeviceProfileLandscape(t *testing.T) {
+	eink := DeviceProfiles["eink-7in5"]
+	if !eink.Landscape(0) || eink.Landscape(90) {
+		t.Error("expected an 800x480 display to be landscape, and portrait when rotated by 90 degrees")
+	}
+	remarkable := DeviceProfiles["remarkable"]
+	if remarkable.Landscape(180) || !remarkable.Landscape(270) {
+		t.Error("expected a 1404x1872 display to be portrait, and landscape when rotated by 270 degrees")
+	}
+}
@@ -0,0 +1,114 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// ParseRecurringEvents reads a file with household events, like waste collection or football
+// practice. Each event is a block of iCalendar properties, separated by empty lines:
+//
+//	# Every other tuesday
+//	SUMMARY:Waste collection
+//	DTSTART;VALUE=DATE:20250107
+//	RRULE:FREQ=WEEKLY;INTERVAL=2
+//
+//	SUMMARY:Football
+//	DTSTART:20250106T170000
+//	DURATION:PT1H30M
+//	RRULE:FREQ=WEEKLY;BYDAY=MO,WE
+//	EXDATE:20250505T170000,20250507T170000
+//	ROW:Alice
+//
+// The properties are SUMMARY, DTSTART, DTEND, DURATION, RRULE, EXDATE and CATEGORIES, as in an
+// iCalendar file, and ROW, with a comma separated list of the rows that the event is shown in,
+// or * for all rows. Events without a ROW are placed like the events of an iCalendar file.
+// Times without a time zone are in the time zone of the calendar.
+// Lines starting with # are comments.
+func ParseRecurringEvents(r io.Reader) ([]Event, error) {
+	var (
+		events    []Event
+		builder   *eventBuilder
+		startLine int // the line number where the current event starts
+	)
+	finish := func() error {
+		if builder == nil {
+			return nil
+		}
+		event, ok, err := builder.finish()
+		switch {
+		case err != nil:
+			return fmt.Errorf("line %d: %w", startLine, err)
+		case event.Summary == "":
+			return fmt.Errorf("line %d: the event has no SUMMARY", startLine)
+		case event.Start.IsZero():
+			return fmt.Errorf("line %d: the event has no DTSTART", startLine)
+		}
+		if ok {
+			events = append(events, event)
+		}
+		builder = nil
+		return nil
+	}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == "" {
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		p, err := parseICSLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if builder == nil {
+			builder = &eventBuilder{}
+			builder.event.UID = fmt.Sprintf("recurring-%d", n)
+			startLine = n
+		}
+		if p.name == "ROW" {
+			for _, row := range splitICSList(p.value) {
+				if row = strings.TrimSpace(row); row != "" {
+					builder.event.Rows = append(builder.event.Rows, row)
+				}
+			}
+			continue
+		}
+		ok, err := builder.set(p)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("line %d: unknown property: %s", n, p.name)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LoadRecurringEvents reads a file with household events, see ParseRecurringEvents
+func LoadRecurringEvents(filename string) ([]Event, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, err := ParseRecurringEvents(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return events, nil
+}
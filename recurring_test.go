@@ -0,0 +1,109 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+const testRecurring = `# Household events
+SUMMARY:Waste collection
+DTSTART;VALUE=DATE:20250107
+RRULE:FREQ=WEEKLY;INTERVAL=2
+
+SUMMARY:Football
+DTSTART;TZID=Europe/Oslo:20250106T170000
+DURATION:PT1H30M
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE
+EXDATE;TZID=Europe/Oslo:20250514T170000
+ROW:Alice
+
+SUMMARY:Piano
+DTSTART;TZID=Europe/Oslo:20250102T180000
+RRULE:FREQ=MONTHLY;BYDAY=1TH
+ROW:Bob,Alice
+`
+
+func TestParseRecurringEvents(t *testing.T) {
+	events, err := ParseRecurringEvents(strings.NewReader(testRecurring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if e := events[0]; e.Summary != "Waste collection" || !e.AllDay || e.RRule == nil || e.RRule.Interval != 2 || len(e.Rows) != 0 {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[1]; e.End.Sub(e.Start) != 90*time.Minute || len(e.ExDates) != 1 || len(e.Rows) != 1 || e.Rows[0] != "Alice" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[2]; len(e.Rows) != 2 || e.Rows[1] != "Alice" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	for _, s := range []string{
+		"SUMMARY:Football\nRRULE:FREQ=WEEKLY\n",
+		"DTSTART:20250106T170000\n",
+		"SUMMARY:Football\nDTSTART:20250106T170000\nRRULE:FREQ=SOMETIMES\n",
+		"SUMMARY:Football\nDTSTART:20250106T170000\nLOCATION:Field\n",
+	} {
+		if _, err := ParseRecurringEvents(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+	if _, err := ParseRecurringEvents(strings.NewReader("SUMMARY:Piano\n\nSUMMARY:Football\nDTSTART:2025\n")); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("expected an error for the event on line 1, got %v", err)
+	}
+}
+
+func TestRecurringEventsInRows(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := ParseRecurringEvents(strings.NewReader(testRecurring))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 19, Names: []string{"Bob", "Alice"}, Events: events, SharedRow: "Home", TimeZone: oslo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Names) != 3 || page.Names[2] != "Home" {
+		t.Fatalf("expected a shared row below the people, got %v", page.Names)
+	}
+	texts := func(week, day, row int) string {
+		var lines []string
+		for _, line := range page.Weeks[week].Days[day].Cells[row].Lines {
+			lines = append(lines, line.Text)
+		}
+		return strings.Join(lines, ", ")
+	}
+	// The waste collection is every other tuesday, in the shared row
+	if s := texts(0, 1, 2); s != "" {
+		t.Errorf("expected no waste collection on the 6th of May, got %q", s)
+	}
+	if s := texts(1, 1, 2); s != "Waste collection" {
+		t.Errorf("expected waste collection on the 13th of May, got %q", s)
+	}
+	// Football on mondays and wednesdays, except on the 14th of May
+	if s := texts(0, 2, 1); !strings.HasSuffix(s, "Football") {
+		t.Errorf("expected football on the 7th of May, got %q", s)
+	}
+	if s := texts(1, 2, 1); s != "" {
+		t.Errorf("expected no football on the 14th of May, got %q", s)
+	}
+	if s := texts(0, 0, 0); s != "" {
+		t.Errorf("expected nothing for Bob on the 5th of May, got %q", s)
+	}
+}
@@ -0,0 +1,344 @@
+package kitchencalendar
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRRulePeriods is the largest number of days, weeks, months or years that are searched
+// for occurrences, so that rules that never match anything do not loop forever
+const maxRRulePeriods = 100000
+
+// WeekdayNum is a weekday in a BYDAY rule part, like MO or -1FR (the last friday)
+type WeekdayNum struct {
+	N       int // the occurrence within the month or year, 0 for every occurrence
+	Weekday time.Weekday
+}
+
+// RRule is a recurrence rule, as described in RFC 5545, section 3.3.10.
+// The BYWEEKNO, BYYEARDAY, BYHOUR, BYMINUTE and BYSECOND rule parts are not supported.
+type RRule struct {
+	Freq       string // DAILY, WEEKLY, MONTHLY or YEARLY
+	Interval   int
+	Count      int       // the number of occurrences, or 0 for no limit
+	Until      time.Time // the last possible occurrence, or the zero time for no limit
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+}
+
+// rruleWeekdays are the two-letter weekday names that are used in BYDAY
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// parseInts parses a comma separated list of integers, that must be within the given limits
+// and can not be 0
+func parseInts(s string, lowest, highest int) ([]int, error) {
+	var numbers []int
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 || n < lowest || n > highest {
+			return nil, fmt.Errorf("%d is out of range", n)
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, nil
+}
+
+// ParseRRule parses the value of an RRULE property, like "FREQ=MONTHLY;BYDAY=-1FR".
+// A date-only UNTIL is interpreted in the given location.
+func ParseRRule(s string, loc *time.Location) (*RRule, error) {
+	r := &RRule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimSpace(s), ";") {
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part: %s", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = fmt.Errorf("%d is out of range", r.Interval)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = fmt.Errorf("%d is out of range", r.Count)
+			}
+		case "UNTIL":
+			r.Until, _, err = parseICSTime(value, loc)
+			if err == nil && len(value) == 8 {
+				// A date-only UNTIL includes the whole day
+				r.Until = r.Until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+		case "BYDAY":
+			for _, field := range strings.Split(strings.ToUpper(value), ",") {
+				if len(field) < 2 {
+					return nil, fmt.Errorf("invalid BYDAY: %s", value)
+				}
+				weekday, ok := rruleWeekdays[field[len(field)-2:]]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY: %s", value)
+				}
+				n := 0
+				if prefix := field[:len(field)-2]; prefix != "" {
+					if n, err = strconv.Atoi(prefix); err != nil || n == 0 || n < -53 || n > 53 {
+						return nil, fmt.Errorf("invalid BYDAY: %s", value)
+					}
+				}
+				r.ByDay = append(r.ByDay, WeekdayNum{N: n, Weekday: weekday})
+			}
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(value, 1, 12)
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseInts(value, -366, 366)
+		case "WKST":
+			// Weeks always start on monday
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in %q: %w", key, s, err)
+		}
+	}
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, fmt.Errorf("missing FREQ in %q", s)
+	default:
+		return nil, fmt.Errorf("unsupported FREQ in %q", s)
+	}
+	return r, nil
+}
+
+// daysIn returns the number of days in the given month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// matchesMonth checks if the month is one of the BYMONTH months, if any
+func (r *RRule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDay checks if the day matches the BYMONTHDAY and BYDAY rule parts, if any,
+// ignoring the occurrence numbers of BYDAY. Used when the frequency is daily or weekly.
+func (r *RRule) matchesDay(t time.Time) bool {
+	if len(r.ByMonthDay) > 0 {
+		found := false
+		n := daysIn(t.Year(), t.Month())
+		for _, md := range r.ByMonthDay {
+			if md == t.Day() || md < 0 && n+md+1 == t.Day() {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 {
+		for _, wd := range r.ByDay {
+			if wd.Weekday == t.Weekday() {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// weekdaysIn returns the days, from first to last (inclusive), that match the given BYDAY entry
+func weekdaysIn(first, last time.Time, wd WeekdayNum) []time.Time {
+	var days []time.Time
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == wd.Weekday {
+			days = append(days, d)
+		}
+	}
+	switch {
+	case wd.N > 0 && wd.N <= len(days):
+		return days[wd.N-1 : wd.N]
+	case wd.N < 0 && -wd.N <= len(days):
+		return days[len(days)+wd.N : len(days)+wd.N+1]
+	case wd.N != 0:
+		return nil
+	}
+	return days
+}
+
+// monthDays returns the days of the given month that match the rule, or the given default
+// day if the rule has neither BYMONTHDAY nor BYDAY
+func (r *RRule) monthDays(year int, month time.Month, defaultDay int) []time.Time {
+	n := daysIn(year, month)
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	var days []time.Time
+	switch {
+	case len(r.ByDay) > 0:
+		for _, wd := range r.ByDay {
+			for _, d := range weekdaysIn(first, first.AddDate(0, 0, n-1), wd) {
+				if r.matchesDay(d) {
+					days = append(days, d)
+				}
+			}
+		}
+	case len(r.ByMonthDay) > 0:
+		for _, md := range r.ByMonthDay {
+			if md < 0 {
+				md = n + md + 1
+			}
+			if md >= 1 && md <= n {
+				days = append(days, first.AddDate(0, 0, md-1))
+			}
+		}
+	case defaultDay <= n:
+		days = append(days, first.AddDate(0, 0, defaultDay-1))
+	}
+	return days
+}
+
+// periodDays returns the days of the n-th period (day, week, month or year) after the start
+// that match the rule, sorted and without duplicates. The second return value is the first day
+// of the period.
+func (r *RRule) periodDays(start time.Time, n int) ([]time.Time, time.Time) {
+	startDay := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	var days []time.Time
+	var periodStart time.Time
+	switch r.Freq {
+	case "DAILY":
+		periodStart = startDay.AddDate(0, 0, n*r.Interval)
+		if r.matchesMonth(periodStart.Month()) && r.matchesDay(periodStart) {
+			days = append(days, periodStart)
+		}
+	case "WEEKLY":
+		monday := startDay.AddDate(0, 0, -((int(startDay.Weekday()) + 6) % 7))
+		periodStart = monday.AddDate(0, 0, 7*n*r.Interval)
+		weekdays := []WeekdayNum{{Weekday: start.Weekday()}}
+		if len(r.ByDay) > 0 {
+			weekdays = r.ByDay
+		}
+		for _, wd := range weekdays {
+			d := periodStart.AddDate(0, 0, (int(wd.Weekday)+6)%7)
+			if r.matchesMonth(d.Month()) {
+				days = append(days, d)
+			}
+		}
+	case "MONTHLY":
+		periodStart = time.Date(start.Year(), start.Month()+time.Month(n*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+		if r.matchesMonth(periodStart.Month()) {
+			days = r.monthDays(periodStart.Year(), periodStart.Month(), start.Day())
+		}
+	case "YEARLY":
+		year := start.Year() + n*r.Interval
+		periodStart = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		switch {
+		case len(r.ByDay) > 0 && len(r.ByMonth) == 0 && len(r.ByMonthDay) == 0:
+			// The n-th weekday of the year
+			for _, wd := range r.ByDay {
+				days = append(days, weekdaysIn(periodStart, time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC), wd)...)
+			}
+		default:
+			months := r.ByMonth
+			if len(months) == 0 {
+				months = []time.Month{start.Month()}
+			}
+			for _, month := range months {
+				days = append(days, r.monthDays(year, month, start.Day())...)
+			}
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	unique := days[:0]
+	for i, d := range days {
+		if i == 0 || !d.Equal(days[i-1]) {
+			unique = append(unique, d)
+		}
+	}
+	return r.setPos(unique), periodStart
+}
+
+// setPos selects the days at the BYSETPOS positions, if any
+func (r *RRule) setPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 {
+		return days
+	}
+	var selected []time.Time
+	for i, d := range days {
+		for _, pos := range r.BySetPos {
+			if pos == i+1 || pos == i-len(days) {
+				selected = append(selected, d)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// Between returns the start times of the occurrences of a repeating event that starts at the
+// given time, from the given time to the given time (inclusive). The occurrences keep the wall
+// clock time of the start, also across daylight saving time changes.
+func (r *RRule) Between(start, from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	loc := start.Location()
+	hour, minute, second := start.Clock()
+	count := 0
+	for n := 0; n < maxRRulePeriods; n++ {
+		days, periodStart := r.periodDays(start, n)
+		if time.Date(periodStart.Year(), periodStart.Month(), periodStart.Day(), 0, 0, 0, 0, loc).After(to) {
+			break
+		}
+		for _, d := range days {
+			t := time.Date(d.Year(), d.Month(), d.Day(), hour, minute, second, start.Nanosecond(), loc)
+			if t.Before(start) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return occurrences
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+			if t.After(to) {
+				return occurrences
+			}
+			if !t.Before(from) {
+				occurrences = append(occurrences, t)
+			}
+		}
+	}
+	return occurrences
+}
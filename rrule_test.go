@@ -0,0 +1,152 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	r, err := ParseRRule("FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR,2MO;COUNT=5", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Freq != "MONTHLY" || r.Interval != 2 || r.Count != 5 || len(r.ByDay) != 2 {
+		t.Errorf("unexpected rule: %+v", r)
+	}
+	if r.ByDay[0] != (WeekdayNum{N: -1, Weekday: time.Friday}) {
+		t.Errorf("unexpected BYDAY: %+v", r.ByDay)
+	}
+	for _, s := range []string{"", "INTERVAL=2", "FREQ=HOURLY", "FREQ=DAILY;BYDAY=XX", "FREQ=DAILY;INTERVAL=0", "FREQ=DAILY;BYMONTH=13"} {
+		if _, err := ParseRRule(s, time.UTC); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestRRuleBetween(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 10, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		rule     string
+		start    time.Time
+		from, to time.Time
+		expected []time.Time
+	}{
+		{
+			"FREQ=WEEKLY;BYDAY=MO,WE", date(2025, 5, 5), date(2025, 5, 1), date(2025, 5, 14),
+			[]time.Time{date(2025, 5, 5), date(2025, 5, 7), date(2025, 5, 12), date(2025, 5, 14)},
+		},
+		{
+			"FREQ=MONTHLY;BYDAY=-1FR", date(2025, 1, 31), date(2025, 1, 1), date(2025, 4, 30),
+			[]time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 28), date(2025, 4, 25)},
+		},
+		{
+			"FREQ=DAILY;INTERVAL=3;COUNT=3", date(2025, 5, 1), date(2025, 5, 1), date(2025, 6, 1),
+			[]time.Time{date(2025, 5, 1), date(2025, 5, 4), date(2025, 5, 7)},
+		},
+		{
+			"FREQ=WEEKLY;UNTIL=20250515", date(2025, 5, 1), date(2025, 5, 1), date(2025, 6, 1),
+			[]time.Time{date(2025, 5, 1), date(2025, 5, 8), date(2025, 5, 15)},
+		},
+		{
+			// Only leap years have the 29th of February
+			"FREQ=YEARLY", date(2024, 2, 29), date(2024, 1, 1), date(2029, 1, 1),
+			[]time.Time{date(2024, 2, 29), date(2028, 2, 29)},
+		},
+		{
+			// The 31st is skipped in months with 30 days
+			"FREQ=MONTHLY", date(2025, 3, 31), date(2025, 4, 1), date(2025, 6, 1),
+			[]time.Time{date(2025, 5, 31)},
+		},
+		{
+			// The last weekday of the month
+			"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", date(2025, 5, 30), date(2025, 5, 1), date(2025, 8, 31),
+			[]time.Time{date(2025, 5, 30), date(2025, 6, 30), date(2025, 7, 31), date(2025, 8, 29)},
+		},
+	}
+	for _, test := range tests {
+		r, err := ParseRRule(test.rule, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := r.Between(test.start, test.from, test.to)
+		if len(got) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.rule, test.expected, got)
+			continue
+		}
+		for i := range got {
+			if !got[i].Equal(test.expected[i]) {
+				t.Errorf("%s: expected %v, got %v", test.rule, test.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestRRuleFifthWeekday(t *testing.T) {
+	// Only some months have a 5th thursday, and the other months are skipped
+	r, err := ParseRRule("FREQ=MONTHLY;BYDAY=5TH", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, time.January, 30, 18, 0, 0, 0, time.UTC)
+	var got []string
+	for _, occurrence := range r.Between(start, start, time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		got = append(got, occurrence.Format("2006-01-02"))
+	}
+	expected := "2025-01-30 2025-05-29 2025-07-31 2025-10-30"
+	if strings.Join(got, " ") != expected {
+		t.Errorf("expected %s, got %v", expected, got)
+	}
+	// The 5th last monday is the first monday, in the months that have five mondays
+	r, err = ParseRRule("FREQ=MONTHLY;BYDAY=-5MO;COUNT=2", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	start = time.Date(2025, time.March, 3, 18, 0, 0, 0, time.UTC)
+	for _, occurrence := range r.Between(start, start, time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)) {
+		got = append(got, occurrence.Format("2006-01-02"))
+	}
+	if expected := "2025-03-03 2025-06-02"; strings.Join(got, " ") != expected {
+		t.Errorf("expected %s, got %v", expected, got)
+	}
+}
+
+func TestRRuleDaylightSavingTime(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip("the time zone database is not available")
+	}
+	r, err := ParseRRule("FREQ=WEEKLY;BYDAY=SU", oslo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The clocks are set forward on the 30th of March 2025 and back on the 26th of October 2025,
+	// but the event is at 17:00 local time every week
+	start := time.Date(2025, time.March, 23, 17, 0, 0, 0, oslo)
+	for _, occurrence := range r.Between(start, start, time.Date(2025, time.November, 2, 23, 0, 0, 0, oslo)) {
+		if occurrence.Hour() != 17 || occurrence.Weekday() != time.Sunday {
+			t.Errorf("expected sundays at 17:00, got %v", occurrence)
+		}
+	}
+	occurrences := r.Between(start, start, time.Date(2025, time.March, 30, 23, 0, 0, 0, oslo))
+	if len(occurrences) != 2 || occurrences[1].Sub(occurrences[0]) != 7*24*time.Hour-time.Hour {
+		t.Errorf("expected the week with the daylight saving time change to be an hour shorter, got %v", occurrences)
+	}
+	// 02:30 does not exist on the day the clocks are set forward, so it is an hour later,
+	// as described in RFC 5545, section 3.3.5
+	start = time.Date(2025, time.March, 23, 2, 30, 0, 0, oslo)
+	occurrences = r.Between(start, start, time.Date(2025, time.April, 6, 23, 0, 0, 0, oslo))
+	if len(occurrences) != 3 || occurrences[1].Hour() != 3 || occurrences[1].Minute() != 30 || occurrences[2].Hour() != 2 {
+		t.Errorf("expected 03:30 on the day the clocks are set forward, got %v", occurrences)
+	}
+	// 02:30 happens twice on the day the clocks are set back, and the event is only there once
+	start = time.Date(2025, time.October, 19, 2, 30, 0, 0, oslo)
+	occurrences = r.Between(start, start, time.Date(2025, time.November, 2, 23, 0, 0, 0, oslo))
+	if len(occurrences) != 3 || occurrences[1].Hour() != 2 {
+		t.Errorf("expected a single occurrence at 02:30 on the day the clocks are set back, got %v", occurrences)
+	}
+}
@@ -0,0 +1,137 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ShiftCode is the meaning of a shift code, like "N" for "Night 19-07"
+type ShiftCode struct {
+	Code        string
+	Description string
+}
+
+// ShiftPattern is the repeating cycle of shifts of one person, that starts at an anchor day
+type ShiftPattern struct {
+	Person string
+	Anchor time.Time   // the first day of the cycle, at midnight UTC
+	Codes  []string    // the shift code of each day of the cycle
+	Legend []ShiftCode // the meaning of the codes that are used in the cycle
+}
+
+// ParseShiftPatterns reads the shift patterns of the people, with the first day of the cycle, the
+// name and the shift code of each day of the cycle on each line. Lines with a code, = and a meaning
+// are the legend for all the patterns. For example, for "2 days, 2 nights, 4 off":
+//
+//	D = Day 07-19
+//	N = Night 19-07
+//	2025-01-06 Alice: D D N N off off off off
+//
+// Empty lines and lines starting with # are skipped.
+func ParseShiftPatterns(r io.Reader) ([]ShiftPattern, error) {
+	var (
+		patterns []ShiftPattern
+		legend   []ShiftCode
+	)
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if code, description, ok := strings.Cut(line, "="); ok {
+			code, description = strings.TrimSpace(code), strings.TrimSpace(description)
+			if code == "" || strings.Contains(code, " ") || description == "" {
+				return nil, fmt.Errorf("line %d: expected a shift code, = and a meaning: %s", lineNumber, line)
+			}
+			legend = append(legend, ShiftCode{Code: code, Description: description})
+			continue
+		}
+		date, rest, _ := strings.Cut(line, " ")
+		anchor, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date, expected YYYY-MM-DD: %s", lineNumber, date)
+		}
+		person, codes, ok := strings.Cut(rest, ":")
+		p := ShiftPattern{Person: strings.TrimSpace(person), Anchor: anchor, Codes: strings.Fields(codes)}
+		if !ok || p.Person == "" || len(p.Codes) == 0 {
+			return nil, fmt.Errorf("line %d: expected a name, : and the shift codes: %s", lineNumber, line)
+		}
+		patterns = append(patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Each pattern gets the meaning of the codes that it uses
+	for i := range patterns {
+		for _, c := range legend {
+			for _, code := range patterns[i].Codes {
+				if code == c.Code {
+					patterns[i].Legend = append(patterns[i].Legend, c)
+					break
+				}
+			}
+		}
+	}
+	return patterns, nil
+}
+
+// LoadShiftPatterns reads the shift patterns of the people from the given file, see ParseShiftPatterns
+func LoadShiftPatterns(filename string) ([]ShiftPattern, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	patterns, err := ParseShiftPatterns(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return patterns, nil
+}
+
+// ShiftAt returns the shift code of the given day
+func (p ShiftPattern) ShiftAt(t time.Time) string {
+	if len(p.Codes) == 0 {
+		return ""
+	}
+	return p.Codes[cycleDay(p.Anchor, t, len(p.Codes))]
+}
+
+// addShifts sets the shift code in the cells of the people with shift patterns
+func addShifts(w *Week, patterns []ShiftPattern, names []string) {
+	for _, p := range patterns {
+		for row, name := range names {
+			if !strings.EqualFold(strings.TrimSpace(name), p.Person) {
+				continue
+			}
+			for i := range w.Days {
+				w.Days[i].Cells[row].Shift = p.ShiftAt(w.Days[i].Time)
+			}
+		}
+	}
+}
+
+// shiftLegends returns the lines of the legend below the name of each row, like "N: Night 19-07",
+// or nil if none of the rows have shift patterns with a legend
+func shiftLegends(patterns []ShiftPattern, names []string) [][]string {
+	var legends [][]string
+	for row, name := range names {
+		for _, p := range patterns {
+			if !strings.EqualFold(strings.TrimSpace(name), p.Person) {
+				continue
+			}
+			if legends == nil {
+				legends = make([][]string, len(names))
+			}
+			for _, c := range p.Legend {
+				legends[row] = append(legends[row], c.Code+": "+c.Description)
+			}
+		}
+	}
+	return legends
+}
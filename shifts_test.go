@@ -0,0 +1,93 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+const testShifts = `# 2 days, 2 nights, 4 off
+D = Day 07-19
+N = Night 19-07
+E = Evening 15-23
+2026-12-21 Alice: D D N N off off off off
+`
+
+func TestParseShiftPatterns(t *testing.T) {
+	patterns, err := ParseShiftPatterns(strings.NewReader(testShifts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patterns) != 1 {
+		t.Fatalf("expected 1 pattern, got %+v", patterns)
+	}
+	p := patterns[0]
+	if p.Person != "Alice" || len(p.Codes) != 8 || p.Codes[2] != "N" {
+		t.Errorf("unexpected pattern: %+v", p)
+	}
+	// Only the codes that are used are in the legend
+	if len(p.Legend) != 2 || p.Legend[1] != (ShiftCode{"N", "Night 19-07"}) {
+		t.Errorf("unexpected legend: %+v", p.Legend)
+	}
+	for _, s := range []string{"2026-12-21 Alice", "2026-12-21 Alice:", "21.12.2026 Alice: D N", "2026-12-21 : D N", "D =", "Day shift = 07-19"} {
+		if _, err := ParseShiftPatterns(strings.NewReader(s)); err == nil {
+			t.Errorf("expected an error for %q", s)
+		}
+	}
+}
+
+func TestShiftAt(t *testing.T) {
+	patterns, err := ParseShiftPatterns(strings.NewReader(testShifts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := patterns[0]
+	// The cycle of 8 days continues across the new year
+	var got []string
+	for d := time.Date(2026, time.December, 21, 0, 0, 0, 0, time.UTC); d.Year() < 2027 || d.Day() <= 8; d = d.AddDate(0, 0, 1) {
+		got = append(got, p.ShiftAt(d))
+	}
+	if s := strings.Join(got, " "); s != "D D N N off off off off D D N N off off off off D D N" {
+		t.Errorf("unexpected shifts: %s", s)
+	}
+	if s := p.ShiftAt(time.Date(2026, time.December, 20, 0, 0, 0, 0, time.UTC)); s != "off" {
+		t.Errorf("expected the day before the anchor to be off, got %s", s)
+	}
+}
+
+func TestAddShifts(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	patterns, err := ParseShiftPatterns(strings.NewReader(testShifts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A page that starts in week 53 of 2026 and continues into 2027
+	page, err := buildPage(cal, tmpl, Options{Year: 2026, Week: 53, Names: []string{"Bob", "Alice"}, Shifts: patterns})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, w := range page.Weeks {
+		for _, day := range w.Days {
+			if day.Cells[0].Shift != "" {
+				t.Errorf("expected no shifts for Bob on %s", day.Date)
+			}
+			got = append(got, day.Cells[1].Shift)
+		}
+	}
+	if s := strings.Join(got, " "); s != "off D D N N off off off off D D N N off" {
+		t.Errorf("unexpected shifts: %s", s)
+	}
+	if len(page.Legends) != 2 || len(page.Legends[0]) != 0 || strings.Join(page.Legends[1], ", ") != "D: Day 07-19, N: Night 19-07" {
+		t.Errorf("unexpected legends: %q", page.Legends)
+	}
+}
@@ -0,0 +1,134 @@
+package kitchencalendar
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// SunDisplay decides what is shown about the sun below the day labels
+type SunDisplay string
+
+const (
+	SunNone      SunDisplay = ""          // nothing, the default
+	SunRiseSet   SunDisplay = "times"     // the times of sunrise and sunset
+	SunDayLength SunDisplay = "daylength" // the time from sunrise to sunset
+)
+
+// ParseSunDisplay parses "none", "times" or "daylength". An empty string gives SunNone.
+func ParseSunDisplay(s string) (SunDisplay, error) {
+	switch display := SunDisplay(strings.ToLower(strings.TrimSpace(s))); display {
+	case "", "none":
+		return SunNone, nil
+	case SunRiseSet, SunDayLength:
+		return display, nil
+	}
+	return SunNone, fmt.Errorf("unknown sun display, expected none, times or daylength: %s", s)
+}
+
+// SunTimes is the sunrise and sunset of a day at a location
+type SunTimes struct {
+	Rise, Set  time.Time // in the time zone of the location, or zero if the sun does not rise or set
+	PolarDay   bool      // the sun stays above the horizon for the whole day, like in Tromsø in June
+	PolarNight bool      // the sun stays below the horizon for the whole day
+}
+
+// DayLength returns the time from sunrise to sunset, 24 hours for polar day and 0 for polar night
+func (s SunTimes) DayLength() time.Duration {
+	switch {
+	case s.PolarDay:
+		return 24 * time.Hour
+	case s.PolarNight:
+		return 0
+	}
+	return s.Set.Sub(s.Rise)
+}
+
+// sunZenith is the angle from straight up to the center of the sun at sunrise and sunset, in
+// degrees, including the refraction of the atmosphere and the radius of the sun
+const sunZenith = 90.833
+
+// solarPosition returns the declination of the sun, in degrees, and the equation of time, in
+// minutes, at the given time. These are the formulas of the NOAA solar calculator.
+func solarPosition(t time.Time) (declination, equationOfTime float64) {
+	jd := float64(t.Unix())/86400 + 2440587.5
+	T := (jd - 2451545) / 36525
+	// The mean longitude and mean anomaly of the sun and the eccentricity of the orbit of the earth
+	L0 := math.Mod(280.46646+T*(36000.76983+T*0.0003032), 360)
+	M := 357.52911 + T*(35999.05029-0.0001537*T)
+	e := 0.016708634 - T*(0.000042037+0.0000001267*T)
+	// The apparent longitude of the sun
+	C := sinDeg(M)*(1.914602-T*(0.004817+0.000014*T)) + sinDeg(2*M)*(0.019993-0.000101*T) + sinDeg(3*M)*0.000289
+	omega := 125.04 - 1934.136*T
+	lambda := L0 + C - 0.00569 - 0.00478*sinDeg(omega)
+	// The obliquity of the ecliptic
+	epsilon := 23 + (26+(21.448-T*(46.815+T*(0.00059-T*0.001813)))/60)/60 + 0.00256*cosDeg(omega)
+	declination = math.Asin(sinDeg(epsilon)*sinDeg(lambda)) * 180 / math.Pi
+	y := math.Pow(math.Tan(epsilon/2*math.Pi/180), 2)
+	equationOfTime = 4 * 180 / math.Pi * (y*sinDeg(2*L0) - 2*e*sinDeg(M) + 4*e*y*sinDeg(M)*cosDeg(2*L0) -
+		0.5*y*y*sinDeg(4*L0) - 1.25*e*e*sinDeg(2*M))
+	return declination, equationOfTime
+}
+
+// sunCosHourAngle returns the cosine of the hour angle of the sun at sunrise and sunset, which is
+// below -1 if the sun does not set and above 1 if the sun does not rise
+func sunCosHourAngle(latitude, declination float64) float64 {
+	return (cosDeg(sunZenith) - sinDeg(latitude)*sinDeg(declination)) / (cosDeg(latitude) * cosDeg(declination))
+}
+
+// SunriseSunset returns the sunrise and sunset of the given day at the given latitude and longitude,
+// in degrees north and east, in the given time zone. It is computed without any network access,
+// and is accurate to within a couple of minutes, except close to polar day and polar night.
+func SunriseSunset(date time.Time, latitude, longitude float64, loc *time.Location) SunTimes {
+	if loc == nil {
+		loc = time.Local
+	}
+	midnight := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	at := func(minutes float64) time.Time {
+		return midnight.Add(time.Duration(math.Round(minutes*60)) * time.Second)
+	}
+	// Find the solar noon, in minutes after midnight UTC, and if the sun rises and sets at all
+	noon := 720 - 4*longitude
+	declination, equationOfTime := solarPosition(at(noon))
+	noon = 720 - 4*longitude - equationOfTime
+	declination, _ = solarPosition(at(noon))
+	switch cosH := sunCosHourAngle(latitude, declination); {
+	case cosH < -1:
+		return SunTimes{PolarDay: true}
+	case cosH > 1:
+		return SunTimes{PolarNight: true}
+	}
+	// Find the sunrise and sunset, with the position of the sun at those times
+	event := func(sign float64) time.Time {
+		minutes := noon
+		for i := 0; i < 3; i++ {
+			declination, equationOfTime := solarPosition(at(minutes))
+			cosH := math.Max(-1, math.Min(1, sunCosHourAngle(latitude, declination)))
+			minutes = 720 - 4*longitude - equationOfTime + sign*4*math.Acos(cosH)*180/math.Pi
+		}
+		return at(minutes).In(loc)
+	}
+	return SunTimes{Rise: event(-1), Set: event(1)}
+}
+
+// sunText returns the text about the sun that is shown below the day label, like "03:53-22:44"
+func sunText(s SunTimes, display SunDisplay) string {
+	switch {
+	case s.PolarDay:
+		return PolarDayString()
+	case s.PolarNight:
+		return PolarNightString()
+	case display == SunDayLength:
+		return FormatDayLength(s.DayLength())
+	}
+	return FormatTime(s.Rise) + "-" + FormatTime(s.Set)
+}
+
+// addSun adds the sunrise and sunset, or the day length, to the days of the given week
+func addSun(w *Week, opts Options) {
+	for i := range w.Days {
+		day := &w.Days[i]
+		day.Sun = sunText(SunriseSunset(day.Time, opts.Latitude, opts.Longitude, opts.TimeZone), opts.Sun)
+	}
+}
@@ -0,0 +1,98 @@
+package kitchencalendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+func TestSunriseSunset(t *testing.T) {
+	oslo, err := time.LoadLocation("Europe/Oslo")
+	if err != nil {
+		t.Skip(err)
+	}
+	close := func(got time.Time, expected string) bool {
+		e, err := time.ParseInLocation("2006-01-02 15:04", expected, oslo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Sub(e).Abs() <= 2*time.Minute
+	}
+	// Oslo at midsummer and midwinter
+	s := SunriseSunset(time.Date(2025, time.June, 21, 0, 0, 0, 0, time.UTC), 59.91, 10.75, oslo)
+	if !close(s.Rise, "2025-06-21 03:53") || !close(s.Set, "2025-06-21 22:44") {
+		t.Errorf("unexpected sunrise and sunset in Oslo at midsummer: %s, %s", s.Rise, s.Set)
+	}
+	s = SunriseSunset(time.Date(2025, time.December, 21, 0, 0, 0, 0, time.UTC), 59.91, 10.75, oslo)
+	if !close(s.Rise, "2025-12-21 09:18") || !close(s.Set, "2025-12-21 15:12") {
+		t.Errorf("unexpected sunrise and sunset in Oslo at midwinter: %s, %s", s.Rise, s.Set)
+	}
+	// Midnight sun and polar night in Tromsø
+	for _, c := range []struct {
+		date                 time.Time
+		polarDay, polarNight bool
+	}{
+		{time.Date(2025, time.May, 17, 0, 0, 0, 0, time.UTC), false, false},
+		{time.Date(2025, time.June, 21, 0, 0, 0, 0, time.UTC), true, false},
+		{time.Date(2025, time.July, 22, 0, 0, 0, 0, time.UTC), true, false},
+		{time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC), false, false},
+		{time.Date(2025, time.December, 21, 0, 0, 0, 0, time.UTC), false, true},
+	} {
+		s := SunriseSunset(c.date, 69.65, 18.96, oslo)
+		if s.PolarDay != c.polarDay || s.PolarNight != c.polarNight {
+			t.Errorf("unexpected sun in Tromsø on %s: %+v", c.date.Format("2006-01-02"), s)
+		}
+		if s.PolarDay && s.DayLength() != 24*time.Hour || s.PolarNight && s.DayLength() != 0 {
+			t.Errorf("unexpected day length in Tromsø on %s: %s", c.date.Format("2006-01-02"), s.DayLength())
+		}
+	}
+	// Sydney, in the southern hemisphere, where June is winter
+	sydney, err := time.LoadLocation("Australia/Sydney")
+	if err != nil {
+		t.Skip(err)
+	}
+	s = SunriseSunset(time.Date(2025, time.June, 21, 0, 0, 0, 0, time.UTC), -33.87, 151.21, sydney)
+	if s.Rise.Format("2006-01-02 15") != "2025-06-21 07" || s.Set.Format("2006-01-02 15") != "2025-06-21 16" {
+		t.Errorf("unexpected sunrise and sunset in Sydney: %s, %s", s.Rise, s.Set)
+	}
+}
+
+func TestParseSunDisplay(t *testing.T) {
+	for s, expected := range map[string]SunDisplay{"": SunNone, "none": SunNone, "Times": SunRiseSet, "daylength": SunDayLength} {
+		if display, err := ParseSunDisplay(s); err != nil || display != expected {
+			t.Errorf("expected %q for %q, got %q, %v", expected, s, display, err)
+		}
+	}
+	if _, err := ParseSunDisplay("sometimes"); err == nil {
+		t.Error("expected an error for an unknown sun display")
+	}
+}
+
+func TestAddSun(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 25, Names: []string{"Bob"}, Sun: SunRiseSet, Latitude: 69.65, Longitude: 18.96, TimeZone: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The sun is shown above the other notes
+	day := page.Weeks[0].Days[0]
+	if day.Sun != PolarDayString() || dayNotes(day)[0].Text != PolarDayString() {
+		t.Errorf("expected midnight sun in Tromsø in June, got %+v", day)
+	}
+	page, err = buildPage(cal, tmpl, Options{Year: 2025, Week: 25, Names: []string{"Bob"}, Sun: SunDayLength, Latitude: 59.91, Longitude: 10.75, TimeZone: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+	day = page.Weeks[0].Days[5]
+	if length := SunriseSunset(day.Time, 59.91, 10.75, time.UTC).DayLength(); length < 18*time.Hour+49*time.Minute || length > 18*time.Hour+53*time.Minute || day.Sun != FormatDayLength(length) {
+		t.Errorf("expected a day length of about 18 hours and 51 minutes in Oslo at midsummer, got %q", day.Sun)
+	}
+}
@@ -0,0 +1,75 @@
+package kitchencalendar
+
+import (
+	"github.com/signintech/gopdf"
+)
+
+// fontAscent is the typographic ascender of the Nunito fonts, relative to the font size
+const fontAscent = 1.011
+
+// Surface is something a calendar page can be drawn onto, like a PDF page or an SVG document.
+// Coordinates are in points, with the origin in the upper left corner.
+type Surface interface {
+	// SetFont selects the font ("regular" or "bold") and the font size used by Text
+	SetFont(fontName string, fontSize int) error
+	SetLineWidth(width float64)
+	SetStrokeColor(r, g, b uint8)
+	SetFillColor(r, g, b uint8)
+	SetTextColor(r, g, b uint8)
+	Line(x1, y1, x2, y2 float64)
+	// FillRect draws a filled rectangle, given the upper left corner and the size
+	FillRect(x, y, width, height float64)
+	// Text draws a line of text, where y is the top of the text
+	Text(x, y float64, text string) error
+}
+
+// PDFSurface draws onto the current page of a PDF document
+type PDFSurface struct {
+	pdf *gopdf.GoPdf
+}
+
+// NewPDFSurface returns a Surface that draws onto the current page of the given PDF document
+func NewPDFSurface(pdf *gopdf.GoPdf) *PDFSurface {
+	return &PDFSurface{pdf: pdf}
+}
+
+// SetFont selects a font that has been added to the PDF document
+func (s *PDFSurface) SetFont(fontName string, fontSize int) error {
+	return s.pdf.SetFont(fontName, "", fontSize)
+}
+
+// SetLineWidth sets the width of the lines that are drawn
+func (s *PDFSurface) SetLineWidth(width float64) {
+	s.pdf.SetLineWidth(width)
+}
+
+// SetStrokeColor sets the color of the lines that are drawn
+func (s *PDFSurface) SetStrokeColor(r, g, b uint8) {
+	s.pdf.SetStrokeColor(r, g, b)
+}
+
+// SetFillColor sets the color of the rectangles that are drawn
+func (s *PDFSurface) SetFillColor(r, g, b uint8) {
+	s.pdf.SetFillColor(r, g, b)
+}
+
+// SetTextColor sets the color of the text that is drawn
+func (s *PDFSurface) SetTextColor(r, g, b uint8) {
+	s.pdf.SetTextColor(r, g, b)
+}
+
+// Line draws a line from (x1, y1) to (x2, y2)
+func (s *PDFSurface) Line(x1, y1, x2, y2 float64) {
+	s.pdf.Line(x1, y1, x2, y2)
+}
+
+// FillRect draws a filled rectangle
+func (s *PDFSurface) FillRect(x, y, width, height float64) {
+	s.pdf.RectFromUpperLeftWithStyle(x, y, width, height, "F")
+}
+
+// Text draws a line of text, where y is the top of the text
+func (s *PDFSurface) Text(x, y float64, text string) error {
+	s.pdf.SetXY(x, y)
+	return s.pdf.Cell(nil, text)
+}
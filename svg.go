@@ -0,0 +1,165 @@
+package kitchencalendar
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+)
+
+// SVGSurface draws onto an SVG document, that can be retrieved with Bytes
+type SVGSurface struct {
+	buf         bytes.Buffer
+	width       float64
+	height      float64
+	fontName    string
+	fontSize    int
+	lineWidth   float64
+	strokeColor string
+	fillColor   string
+	textColor   string
+	path        bytes.Buffer // connected lines that are not yet written to buf
+	pathX       float64
+	pathY       float64
+}
+
+// NewSVGSurface returns a new SVG surface of the given size, in points
+func NewSVGSurface(width, height float64) *SVGSurface {
+	return &SVGSurface{
+		width:       width,
+		height:      height,
+		fontName:    "regular",
+		fontSize:    12,
+		lineWidth:   1.0,
+		strokeColor: "#000000",
+		fillColor:   "#000000",
+		textColor:   "#000000",
+	}
+}
+
+// hexColor returns the given color on the form #rrggbb
+func hexColor(r, g, b uint8) string {
+	return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+}
+
+// SetFont selects the font ("regular" or "bold") and font size
+func (s *SVGSurface) SetFont(fontName string, fontSize int) error {
+	switch fontName {
+	case "regular", "bold":
+	default:
+		return fmt.Errorf("unknown font: %s", fontName)
+	}
+	s.fontName = fontName
+	s.fontSize = fontSize
+	return nil
+}
+
+// SetLineWidth sets the width of the lines that are drawn
+func (s *SVGSurface) SetLineWidth(width float64) {
+	s.flushPath()
+	s.lineWidth = width
+}
+
+// SetStrokeColor sets the color of the lines that are drawn
+func (s *SVGSurface) SetStrokeColor(r, g, b uint8) {
+	s.flushPath()
+	s.strokeColor = hexColor(r, g, b)
+}
+
+// SetFillColor sets the color of the rectangles that are drawn
+func (s *SVGSurface) SetFillColor(r, g, b uint8) {
+	s.fillColor = hexColor(r, g, b)
+}
+
+// SetTextColor sets the color of the text that is drawn
+func (s *SVGSurface) SetTextColor(r, g, b uint8) {
+	s.textColor = hexColor(r, g, b)
+}
+
+// Line draws a line from (x1, y1) to (x2, y2).
+// Lines that continue where the previous line ended are collected into a single path.
+func (s *SVGSurface) Line(x1, y1, x2, y2 float64) {
+	if s.path.Len() == 0 || x1 != s.pathX || y1 != s.pathY {
+		s.flushPath()
+		fmt.Fprintf(&s.path, "M%.2f %.2f", x1, y1)
+	}
+	fmt.Fprintf(&s.path, "L%.2f %.2f", x2, y2)
+	s.pathX, s.pathY = x2, y2
+}
+
+// flushPath writes the collected lines to the document
+func (s *SVGSurface) flushPath() {
+	if s.path.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(&s.buf, "<path d=\"%s\" fill=\"none\" stroke=\"%s\" stroke-width=\"%.2f\"/>\n", s.path.String(), s.strokeColor, s.lineWidth)
+	s.path.Reset()
+}
+
+// FillRect draws a filled rectangle
+func (s *SVGSurface) FillRect(x, y, width, height float64) {
+	s.flushPath()
+	fmt.Fprintf(&s.buf, "<rect x=\"%.2f\" y=\"%.2f\" width=\"%.2f\" height=\"%.2f\" fill=\"%s\"/>\n", x, y, width, height, s.fillColor)
+}
+
+// Text draws a line of text, where y is the top of the text
+func (s *SVGSurface) Text(x, y float64, text string) error {
+	s.flushPath()
+	weight := "normal"
+	if s.fontName == "bold" {
+		weight = "bold"
+	}
+	baseline := y + float64(s.fontSize)*fontAscent
+	fmt.Fprintf(&s.buf, "<text x=\"%.2f\" y=\"%.2f\" font-family=\"Nunito, sans-serif\" font-weight=\"%s\" font-size=\"%d\" fill=\"%s\" xml:space=\"preserve\">%s</text>\n", x, baseline, weight, s.fontSize, s.textColor, html.EscapeString(text))
+	return nil
+}
+
+// Element returns the drawing as an <svg> element, without the XML declaration
+func (s *SVGSurface) Element() []byte {
+	s.flushPath()
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%.2fpt\" height=\"%.2fpt\" viewBox=\"0 0 %.2f %.2f\">\n", s.width, s.height, s.width, s.height)
+	fmt.Fprintf(&out, "<rect width=\"100%%\" height=\"100%%\" fill=\"#ffffff\"/>\n")
+	out.Write(s.buf.Bytes())
+	out.WriteString("</svg>\n")
+	return out.Bytes()
+}
+
+// Bytes returns the drawing as a standalone SVG document
+func (s *SVGSurface) Bytes() []byte {
+	return append([]byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n"), s.Element()...)
+}
+
+// GenerateSVG generates an SVG calendar for the given year and week, using the default template
+func GenerateSVG(year, week int, names []string, drawing bool) ([]byte, error) {
+	return GenerateSVGWithOptions(Options{Year: year, Week: week, Names: names, Drawing: drawing})
+}
+
+// GenerateSVGWithOptions generates an SVG calendar, laid out by the template in the given options
+func GenerateSVGWithOptions(opts Options) ([]byte, error) {
+	cal, err := NewCalendar()
+	if err != nil {
+		return []byte{}, err
+	}
+	tmpl, err := opts.template()
+	if err != nil {
+		return []byte{}, err
+	}
+	size := pageSize(tmpl)
+	svg := NewSVGSurface(size.W, size.H)
+	if err := drawRegions(svg, cal, tmpl, opts); err != nil {
+		return []byte{}, err
+	}
+	return svg.Bytes(), nil
+}
+
+// WriteSVG writes an SVG calendar to the given writer, laid out by the template in the given options.
+// The whole drawing is made in memory before it is written.
+func WriteSVG(w io.Writer, opts Options) error {
+	data, err := GenerateSVGWithOptions(opts)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,30 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSVGSurface(t *testing.T) {
+	svg := NewSVGSurface(100, 50)
+	svg.Line(0, 0, 10, 10)
+	svg.Line(10, 10, 20, 0)
+	svg.Line(50, 50, 60, 60)
+	if err := svg.Text(5, 5, "Tom & Jerry <3"); err != nil {
+		t.Fatal(err)
+	}
+	data := string(svg.Bytes())
+
+	if n := strings.Count(data, "<path"); n != 2 {
+		t.Errorf("expected the connected lines to be collected into 2 paths, got %d", n)
+	}
+	if !strings.Contains(data, `d="M0.00 0.00L10.00 10.00L20.00 0.00"`) {
+		t.Errorf("expected the first two lines to be a single path, got:\n%s", data)
+	}
+	if !strings.Contains(data, "Tom &amp; Jerry &lt;3") {
+		t.Errorf("expected the text to be escaped, got:\n%s", data)
+	}
+	if err := svg.SetFont("italic", 12); err == nil {
+		t.Error("expected an error when selecting an unknown font")
+	}
+}
@@ -0,0 +1,155 @@
+package kitchencalendar
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// The region types that can be used in a page template
+const (
+	RegionTitle     = "title"
+	RegionDrawing   = "drawing"
+	RegionWeek      = "week"
+	RegionNotes     = "notes"
+	RegionMiniMonth = "minimonth"
+	RegionText      = "text"
+)
+
+//go:embed templates/*.json
+var templateFS embed.FS
+
+// Style is a named text and line style that regions can refer to
+type Style struct {
+	Font      string  `json:"font"` // "regular" or "bold"
+	Size      int     `json:"size"`
+	LineWidth float64 `json:"lineWidth,omitempty"`
+}
+
+// Region is a rectangular part of a page, with a type that decides what is drawn there
+type Region struct {
+	Type   string  `json:"type"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	Style  string  `json:"style,omitempty"`
+	Week   int     `json:"week,omitempty"`  // week offset from the first week on the page, for "week"
+	Month  int     `json:"month,omitempty"` // month offset from the month of the first week, for "minimonth"
+	Text   string  `json:"text,omitempty"`  // the text for "text" and the label for "notes"
+	Lines  int     `json:"lines,omitempty"` // the number of ruled lines for "notes"
+}
+
+// Template describes the layout of a calendar page, as a list of regions
+type Template struct {
+	Name     string           `json:"name"`
+	PageSize string           `json:"pageSize,omitempty"` // "A4" or "letter", the default is $PAPERSIZE
+	Width    float64          `json:"width,omitempty"`    // custom page width in points, overrides PageSize
+	Height   float64          `json:"height,omitempty"`   // custom page height in points, overrides PageSize
+	Styles   map[string]Style `json:"styles,omitempty"`
+	Regions  []Region         `json:"regions"`
+}
+
+// DefaultTemplate returns the built-in template, with two weeks on each page
+func DefaultTemplate() (*Template, error) {
+	return BuiltinTemplate("default")
+}
+
+// BuiltinTemplate returns one of the built-in templates, for example "default" or "landscape"
+func BuiltinTemplate(name string) (*Template, error) {
+	data, err := templateFS.ReadFile("templates/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in template named %q", name)
+	}
+	return ParseTemplate(data)
+}
+
+// LoadTemplate reads and parses a JSON page template from the given filename
+func LoadTemplate(filename string) (*Template, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := ParseTemplate(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return tmpl, nil
+}
+
+// ParseTemplate parses and validates a JSON page template
+func ParseTemplate(data []byte) (*Template, error) {
+	var tmpl Template
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, err
+	}
+	if err := tmpl.Validate(); err != nil {
+		return nil, err
+	}
+	return &tmpl, nil
+}
+
+// Validate checks that all regions have a known type and refer to existing styles
+func (tmpl *Template) Validate() error {
+	if len(tmpl.Regions) == 0 {
+		return errors.New("the template has no regions")
+	}
+	for i, r := range tmpl.Regions {
+		switch r.Type {
+		case RegionTitle, RegionText:
+		case RegionDrawing, RegionNotes, RegionMiniMonth:
+			if r.Width <= 0 || r.Height <= 0 {
+				return fmt.Errorf("region %d (%s) needs a width and a height", i, r.Type)
+			}
+		case RegionWeek:
+			if r.Width <= 0 || r.Height <= weekHeaderHeight {
+				return fmt.Errorf("region %d (%s) needs a width and a height larger than %.0f", i, r.Type, weekHeaderHeight)
+			}
+			if r.Week < 0 {
+				return fmt.Errorf("region %d (%s) has a negative week offset", i, r.Type)
+			}
+		default:
+			return fmt.Errorf("region %d has an unknown type: %q", i, r.Type)
+		}
+		if r.Style != "" {
+			if _, ok := tmpl.Styles[r.Style]; !ok {
+				return fmt.Errorf("region %d (%s) refers to an unknown style: %q", i, r.Type, r.Style)
+			}
+		}
+	}
+	return nil
+}
+
+// Weeks returns the number of weeks that are shown on each page
+func (tmpl *Template) Weeks() int {
+	weeks := 0
+	for _, r := range tmpl.Regions {
+		if r.Type == RegionWeek && r.Week+1 > weeks {
+			weeks = r.Week + 1
+		}
+	}
+	if weeks == 0 {
+		return 1
+	}
+	return weeks
+}
+
+// style returns the style for the given region, or the given fallback style
+func (tmpl *Template) style(r Region, fallback Style) Style {
+	s, ok := tmpl.Styles[r.Style]
+	if !ok {
+		return fallback
+	}
+	if s.Font == "" {
+		s.Font = fallback.Font
+	}
+	if s.Size == 0 {
+		s.Size = fallback.Size
+	}
+	if s.LineWidth == 0 {
+		s.LineWidth = fallback.LineWidth
+	}
+	return s
+}
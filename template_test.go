@@ -0,0 +1,35 @@
+package kitchencalendar
+
+import "testing"
+
+func TestDefaultTemplate(t *testing.T) {
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatalf("DefaultTemplate() returned an error: %v", err)
+	}
+	if weeks := tmpl.Weeks(); weeks != 2 {
+		t.Errorf("DefaultTemplate().Weeks() = %d, want 2", weeks)
+	}
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		data    string
+		wantErr bool
+	}{
+		{`{"regions": [{"type": "title", "x": 10, "y": 10}]}`, false},
+		{`{"regions": [{"type": "week", "x": 10, "y": 10, "width": 500, "height": 300, "week": 2}]}`, false},
+		{`{"regions": []}`, true},
+		{`{"regions": [{"type": "clock", "x": 10, "y": 10}]}`, true},
+		{`{"regions": [{"type": "week", "x": 10, "y": 10, "width": 500, "height": 20}]}`, true},
+		{`{"regions": [{"type": "notes", "x": 10, "y": 10}]}`, true},
+		{`{"regions": [{"type": "title", "x": 10, "y": 10, "style": "missing"}]}`, true},
+		{`{"regions": `, true},
+	}
+	for _, tt := range tests {
+		_, err := ParseTemplate([]byte(tt.data))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTemplate(%s) returned error %v, wantErr %v", tt.data, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes, for highlighting red days in the terminal
+const (
+	ansiBoldRed = "\x1b[1;31m"
+	ansiBold    = "\x1b[1m"
+	ansiReset   = "\x1b[0m"
+)
+
+const (
+	minTextDayWidth = 3 // the narrowest day column in the text table, in characters
+	textRowsPerName = 2 // the number of lines for each person in the text table
+
+	maxTextLegendWidth = 14 // the widest the legends below the names make the name column, in characters
+)
+
+// textIcons are the icons that are shown before the lines in the cells, as characters that
+// are two columns wide in a terminal
+var textIcons = map[string]string{
+	IconCake:         "🎂",
+	IconNewMoon:      "🌑",
+	IconFirstQuarter: "🌓",
+	IconFullMoon:     "🌕",
+	IconLastQuarter:  "🌗",
+}
+
+// textMoonIcons are the phases of the moon, as characters that are one column wide, with the dark part filled
+var textMoonIcons = map[string]string{
+	IconNewMoon:      "●",
+	IconFirstQuarter: "◐",
+	IconFullMoon:     "○",
+	IconLastQuarter:  "◑",
+}
+
+// padRight pads the given string with spaces, so that it is the given number of characters wide
+func padRight(s string, width int) string {
+	return fillRight(s, width, " ")
+}
+
+// fillRight pads the given string with the given fill character, so that it is the given number of characters wide
+func fillRight(s string, width int, fill string) string {
+	if n := width - utf8.RuneCountInString(s); n > 0 {
+		return s + strings.Repeat(fill, n)
+	}
+	return s
+}
+
+// wrapLabel splits a label into lines that are at most the given number of characters wide.
+// Words that are too long are shortened.
+func wrapLabel(label string, width int) []string {
+	if utf8.RuneCountInString(label) <= width {
+		return []string{label}
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(label) {
+		word = shorten(word, width)
+		switch {
+		case line == "":
+			line = word
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+// shorten shortens the text to the given number of characters, ending with … if it is too long
+func shorten(text string, width int) string {
+	if runes := []rune(text); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return text
+}
+
+// textBorder returns a horizontal border of the text table, like ├──┼──┤
+func textBorder(left, middle, right string, nameWidth, dayWidth int) string {
+	var sb strings.Builder
+	sb.WriteString(left)
+	sb.WriteString(strings.Repeat("─", nameWidth))
+	for i := 0; i < 7; i++ {
+		sb.WriteString(middle + strings.Repeat("─", dayWidth))
+	}
+	sb.WriteString(right)
+	return sb.String()
+}
+
+// GenerateText renders the weeks of a calendar page as tables drawn with box-drawing characters,
+// that fit within the given number of columns. If color is true, ANSI escape codes are used for
+// writing red days in bold red and the titles in bold.
+func GenerateText(opts Options, width int, color bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := WriteText(&buf, opts, width, color); err != nil {
+		return []byte{}, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteText writes the weeks of a calendar page as text tables to the given writer, like GenerateText.
+// The tables are written row by row, as they are made.
+func WriteText(w io.Writer, opts Options, width int, color bool) error {
+	buf := bufio.NewWriter(w)
+	if err := writeText(buf, opts, width, color); err != nil {
+		return err
+	}
+	return buf.Flush()
+}
+
+// writeText writes the weeks of a calendar page as text tables to the given buffered writer
+func writeText(buf *bufio.Writer, opts Options, width int, color bool) error {
+	cal, err := NewCalendar()
+	if err != nil {
+		return err
+	}
+	tmpl, err := opts.template()
+	if err != nil {
+		return err
+	}
+	page, err := buildPage(cal, tmpl, opts)
+	if err != nil {
+		return err
+	}
+
+	bold := func(s string) string {
+		if color {
+			return ansiBold + s + ansiReset
+		}
+		return s
+	}
+
+	nameWidth := 1
+	for _, name := range page.Names {
+		nameWidth = max(nameWidth, utf8.RuneCountInString(name))
+	}
+	for _, legend := range page.Legends {
+		for _, line := range legend {
+			nameWidth = max(nameWidth, min(utf8.RuneCountInString(line), maxTextLegendWidth))
+		}
+	}
+	// The name column, 7 day columns and 9 vertical lines
+	dayWidth := max((width-nameWidth-9)/7, minTextDayWidth)
+	tableWidth := nameWidth + 7*dayWidth + 9
+
+	fmt.Fprintln(buf, bold(page.Title))
+
+	for _, w := range page.Weeks {
+		// The week number to the left and the dates to the right
+		spacing := max(tableWidth-utf8.RuneCountInString(w.Label)-utf8.RuneCountInString(w.Period), 1)
+		fmt.Fprintf(buf, "\n%s%s%s\n", bold(w.Label), strings.Repeat(" ", spacing), w.Period)
+
+		// Find the labels for each day, wrapped to fit in the day columns, followed by the notes
+		var (
+			labels     [][]dayNote
+			labelLines = 1
+			noteLines  = 0
+		)
+		for _, day := range w.Days {
+			var label []dayNote
+			for _, line := range wrapLabel(day.Label, dayWidth) {
+				label = append(label, dayNote{Text: line})
+			}
+			labelLines = max(labelLines, len(label))
+			noteLines = max(noteLines, len(dayNotes(day)))
+			labels = append(labels, label)
+		}
+		for i, day := range w.Days {
+			for len(labels[i]) < labelLines {
+				labels[i] = append(labels[i], dayNote{})
+			}
+			for _, note := range dayNotes(day) {
+				// Without colors, bold notes are marked with a *
+				if note.Bold && !color {
+					note.Text = "*" + note.Text
+				}
+				note.Text = shorten(note.Text, dayWidth)
+				labels[i] = append(labels[i], note)
+			}
+		}
+		headerLines := labelLines + noteLines
+
+		fmt.Fprintln(buf, textBorder("┌", "┬", "┐", nameWidth, dayWidth))
+		for line := 0; line < headerLines; line++ {
+			buf.WriteString("│" + strings.Repeat(" ", nameWidth))
+			for i, label := range labels {
+				text, textBold := "", false
+				if line < len(label) {
+					text, textBold = label[line].Text, label[line].Bold
+				}
+				// The phase of the moon and, without colors, a * for red days are shown in the upper right corner
+				suffix := ""
+				if line == 0 {
+					suffix = textMoonIcons[w.Days[i].Moon]
+					if w.Days[i].Red && !color {
+						suffix += "*"
+					}
+					if utf8.RuneCountInString(text)+utf8.RuneCountInString(suffix) > dayWidth {
+						suffix = ""
+					}
+				}
+				padding := strings.Repeat(" ", dayWidth-utf8.RuneCountInString(text)-utf8.RuneCountInString(suffix))
+				switch {
+				case w.Days[i].Red && color:
+					text = ansiBoldRed + text + ansiReset
+				case textBold && color:
+					text = ansiBold + text + ansiReset
+				}
+				buf.WriteString("│" + text + padding + suffix)
+			}
+			buf.WriteString("│\n")
+		}
+		fmt.Fprintln(buf, textBorder("├", "┼", "┤", nameWidth, dayWidth))
+
+		// One block of rows for each person
+		for i, name := range page.Names {
+			rows := textRowsPerName
+			if i < len(page.Legends) {
+				rows = max(rows, 1+len(page.Legends[i]))
+			}
+			for row := 0; row < rows; row++ {
+				text := ""
+				if row == 0 {
+					text = name
+				} else if i < len(page.Legends) && row-1 < len(page.Legends[i]) {
+					// The legend, like the meaning of the shift codes, is shown below the name
+					text = shorten(page.Legends[i][row-1], nameWidth)
+				}
+				buf.WriteString("│" + padRight(text, nameWidth))
+				for _, day := range w.Days {
+					text, textWidth := "", dayWidth
+					// The shift code is shown in the upper right corner of the cell
+					shift := ""
+					if row == 0 && day.Cells[i].Shift != "" {
+						shift = shorten(day.Cells[i].Shift, dayWidth/2)
+						textWidth -= utf8.RuneCountInString(shift)
+					}
+					if lines := cellLines(day.Cells[i].Lines, rows); row < len(lines) {
+						text = lines[row].Text
+						if lines[row].Checkbox {
+							text = "☐ " + text
+						}
+						if icon, ok := textIcons[lines[row].Icon]; ok {
+							// The icon takes up one more column than it has characters
+							text = icon + " " + text
+							textWidth--
+						}
+						text = shorten(text, textWidth)
+					}
+					switch {
+					case day.Cells[i].Shaded:
+						// The days of periods, like school holidays, are shaded
+						buf.WriteString("│" + fillRight(text, textWidth, "░") + shift)
+					case day.Cells[i].Responsible:
+						// The turns of custody patterns are dotted
+						buf.WriteString("│" + fillRight(text, textWidth, "·") + shift)
+					default:
+						buf.WriteString("│" + padRight(text, textWidth) + shift)
+					}
+				}
+				buf.WriteString("│\n")
+			}
+			if i < len(page.Names)-1 {
+				fmt.Fprintln(buf, textBorder("├", "┼", "┤", nameWidth, dayWidth))
+			} else {
+				fmt.Fprintln(buf, textBorder("└", "┴", "┘", nameWidth, dayWidth))
+			}
+		}
+	}
+	return nil
+}
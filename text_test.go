@@ -0,0 +1,37 @@
+package kitchencalendar
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWrapLabel(t *testing.T) {
+	tests := []struct {
+		label    string
+		width    int
+		expected []string
+	}{
+		{"Mandag 12.", 12, []string{"Mandag 12."}},
+		{"Mandag 12.", 10, []string{"Mandag 12."}},
+		{"Mandag 12.", 9, []string{"Mandag", "12."}},
+		{"Søndag 18.", 3, []string{"Sø…", "18."}},
+		{"Mon. 30th", 6, []string{"Mon.", "30th"}},
+	}
+	for _, tt := range tests {
+		got := wrapLabel(tt.label, tt.width)
+		if !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("wrapLabel(%q, %d) = %q, want %q", tt.label, tt.width, got, tt.expected)
+		}
+	}
+}
+
+func TestTextBorder(t *testing.T) {
+	border := textBorder("┌", "┬", "┐", 5, 3)
+	if expected := "┌─────┬───┬───┬───┬───┬───┬───┬───┐"; border != expected {
+		t.Errorf("textBorder() = %q, want %q", border, expected)
+	}
+	if n := utf8.RuneCountInString(border); n != 5+7*3+9 {
+		t.Errorf("textBorder() is %d characters wide, want %d", n, 5+7*3+9)
+	}
+}
@@ -0,0 +1,107 @@
+package kitchencalendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// priorityMarker is shown before the tasks that have a priority, like (A)
+const priorityMarker = "!"
+
+// isTodoTag checks if the word is a key:value tag of a todo.txt task, like "due:2025-05-17" or "t:2025-05-10"
+func isTodoTag(word string) bool {
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" || strings.HasPrefix(value, "/") {
+		// Not a tag, or a link like https://example.com
+		return false
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseTodoTxt reads the tasks of a todo.txt file that have a due date, as all-day events with a
+// box to tick off on the due date. For example:
+//
+//	(A) Call the plumber +house due:2025-05-14
+//	2025-05-01 Pack the football bag @Alice due:2025-05-15
+//	x 2025-05-02 Buy a present due:2025-05-16
+//
+// The +project and @context markers are the categories of the events, so that they are shown in
+// the row of the person with that name, or where the event rules place them. Completed tasks,
+// starting with x, and tasks without a due date are skipped. Tasks with a priority are marked with !.
+func ParseTodoTxt(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 || words[0] == "x" {
+			continue
+		}
+		priority := false
+		if w := words[0]; len(w) == 3 && w[0] == '(' && w[1] >= 'A' && w[1] <= 'Z' && w[2] == ')' {
+			priority, words = true, words[1:]
+		}
+		if len(words) > 0 {
+			// Skip the creation date
+			if _, err := time.Parse("2006-01-02", words[0]); err == nil {
+				words = words[1:]
+			}
+		}
+		e := Event{UID: fmt.Sprintf("todo-%d", lineNumber), AllDay: true, Task: true}
+		var text []string
+		for _, word := range words {
+			switch {
+			case strings.HasPrefix(word, "due:"):
+				due, err := time.Parse("2006-01-02", strings.TrimPrefix(word, "due:"))
+				if err != nil {
+					return nil, fmt.Errorf("line %d: invalid due date, expected due:YYYY-MM-DD: %s", lineNumber, word)
+				}
+				e.Start, e.End = due, due.AddDate(0, 0, 1)
+			case len(word) > 1 && (word[0] == '+' || word[0] == '@'):
+				e.Categories = append(e.Categories, word[1:])
+			case isTodoTag(word):
+				// Other tags, like t: for the threshold date, are not shown
+			default:
+				text = append(text, word)
+			}
+		}
+		if e.Start.IsZero() {
+			continue
+		}
+		e.Summary = strings.Join(text, " ")
+		if e.Summary == "" {
+			return nil, fmt.Errorf("line %d: the task has no text", lineNumber)
+		}
+		if priority {
+			e.Summary = priorityMarker + " " + e.Summary
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// LoadTodoTxt reads the tasks with a due date from the given todo.txt file, see ParseTodoTxt
+func LoadTodoTxt(filename string) ([]Event, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	events, err := ParseTodoTxt(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,76 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+const testTodoTxt = `(A) Call the plumber +house due:2025-05-14
+2025-05-01 Pack the football bag @Alice due:2025-05-15 t:2025-05-10
+x 2025-05-02 Buy a present @Bob due:2025-05-16
+Read https://example.com/a +reading
+
+(B) 2025-05-03 Dentist @Bob +health due:2025-05-16
+`
+
+func TestParseTodoTxt(t *testing.T) {
+	events, err := ParseTodoTxt(strings.NewReader(testTodoTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The completed task and the task without a due date are skipped
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", events)
+	}
+	if e := events[0]; e.Summary != priorityMarker+" Call the plumber" || !e.AllDay || !e.Task || !e.Start.Equal(time.Date(2025, time.May, 14, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[1]; e.Summary != "Pack the football bag" || strings.Join(e.Categories, ",") != "Alice" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	if e := events[2]; e.Summary != priorityMarker+" Dentist" || strings.Join(e.Categories, ",") != "Bob,health" {
+		t.Errorf("unexpected event: %+v", e)
+	}
+	for _, s := range []string{"Call the plumber due:2025-05-32", "+house due:2025-05-14"} {
+		if _, err := ParseTodoTxt(strings.NewReader("\n" + s)); err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected an error on line 2 for %q, got %v", s, err)
+		}
+	}
+}
+
+func TestAddTodoTxtEvents(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := ParseTodoTxt(strings.NewReader(testTodoTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := ParseEventRules("category:house=Bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 20, Names: []string{"Alice", "Bob"}, Events: events, EventRules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	days := page.Weeks[0].Days
+	// The +house project is placed by the rule, and the @Alice and @Bob contexts in the rows with those names
+	for _, c := range []struct {
+		day, row int
+		text     string
+	}{{2, 1, priorityMarker + " Call the plumber"}, {3, 0, "Pack the football bag"}, {4, 1, priorityMarker + " Dentist"}} {
+		lines := days[c.day].Cells[c.row].Lines
+		if len(lines) != 1 || lines[0].Text != c.text || !lines[0].Checkbox {
+			t.Errorf("expected a task %q on %s, got %+v", c.text, days[c.day].Date, lines)
+		}
+	}
+}
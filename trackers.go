@@ -0,0 +1,136 @@
+package kitchencalendar
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Tracker is a row with small boxes to tick off each day, like for vitamins, piano practice or medicine
+type Tracker struct {
+	Name     string
+	Person   string                    // the person that the row belongs to, shown below the name, if any
+	Boxes    []string                  // the labels of the boxes of each day, like "morning" and "evening", or "" for no label
+	Weekdays []time.Weekday            // the boxes are only shown on these weekdays, if any
+	Days     map[time.Weekday][]string // the labels of the boxes of some weekdays, instead of Boxes
+}
+
+// trackerFile is how a tracker is written in a trackers file
+type trackerFile struct {
+	Name     string              `json:"name"`
+	Person   string              `json:"person,omitempty"`
+	Boxes    []string            `json:"boxes,omitempty"`
+	Count    int                 `json:"count,omitempty"`
+	Weekdays []string            `json:"weekdays,omitempty"`
+	Days     map[string][]string `json:"days,omitempty"`
+}
+
+// ParseTrackers parses a JSON list of tracker rows, like:
+//
+//	[{"name": "Vitamins"},
+//	 {"name": "Piano practice", "person": "Alice", "count": 2, "weekdays": ["monday", "wednesday", "friday"]},
+//	 {"name": "Medicine", "person": "Bob", "boxes": ["morning", "evening"], "days": {"sunday": ["evening"]}}]
+//
+// There is one box each day if neither boxes nor count are given.
+func ParseTrackers(data []byte) ([]Tracker, error) {
+	var files []trackerFile
+	if err := json.Unmarshal(data, &files); err != nil {
+		return nil, err
+	}
+	trackers := make([]Tracker, 0, len(files))
+	for _, f := range files {
+		if f.Name == "" {
+			return nil, errors.New("a tracker has no name")
+		}
+		if f.Count < 0 {
+			return nil, fmt.Errorf("the tracker %q has a negative number of boxes", f.Name)
+		}
+		if f.Count > 0 && len(f.Boxes) > 0 {
+			return nil, fmt.Errorf("the tracker %q has both boxes and a count", f.Name)
+		}
+		t := Tracker{Name: f.Name, Person: f.Person, Boxes: f.Boxes}
+		if len(t.Boxes) == 0 {
+			t.Boxes = make([]string, max(f.Count, 1))
+		}
+		for _, s := range f.Weekdays {
+			weekday, err := parseWeekday(s)
+			if err != nil {
+				return nil, fmt.Errorf("the tracker %q: %w", f.Name, err)
+			}
+			t.Weekdays = append(t.Weekdays, weekday)
+		}
+		for s, boxes := range f.Days {
+			weekday, err := parseWeekday(s)
+			if err != nil {
+				return nil, fmt.Errorf("the tracker %q: %w", f.Name, err)
+			}
+			if t.Days == nil {
+				t.Days = make(map[time.Weekday][]string)
+			}
+			t.Days[weekday] = boxes
+		}
+		trackers = append(trackers, t)
+	}
+	return trackers, nil
+}
+
+// LoadTrackers reads a JSON list of tracker rows from the given file, see ParseTrackers
+func LoadTrackers(filename string) ([]Tracker, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	trackers, err := ParseTrackers(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return trackers, nil
+}
+
+// BoxesOn returns the labels of the boxes of the given day, or nil if there are no boxes that day
+func (t Tracker) BoxesOn(d time.Time) []string {
+	if boxes, ok := t.Days[d.Weekday()]; ok {
+		return boxes
+	}
+	if len(t.Weekdays) > 0 {
+		found := false
+		for _, weekday := range t.Weekdays {
+			if weekday == d.Weekday() {
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return t.Boxes
+}
+
+// addTrackers adds a line with a checkbox for each box to the cells of the tracker rows,
+// which are the rows after the given first row
+func addTrackers(w *Week, trackers []Tracker, first int) {
+	for j, t := range trackers {
+		for i, day := range w.Days {
+			for _, label := range t.BoxesOn(day.Time) {
+				w.Days[i].Cells[first+j].Lines = append(w.Days[i].Cells[first+j].Lines, Line{Text: label, Checkbox: true})
+			}
+		}
+	}
+}
+
+// addTrackerLegends adds the person of each tracker row below the name of the row,
+// where the tracker rows are the rows after the given first row
+func addTrackerLegends(legends [][]string, trackers []Tracker, first int) [][]string {
+	for j, t := range trackers {
+		if t.Person == "" {
+			continue
+		}
+		if legends == nil {
+			legends = make([][]string, first+len(trackers))
+		}
+		legends[first+j] = append(legends[first+j], t.Person)
+	}
+	return legends
+}
@@ -0,0 +1,122 @@
+package kitchencalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xyproto/kal"
+)
+
+const testTrackers = `[
+	{"name": "Vitamins"},
+	{"name": "Piano practice", "person": "Alice", "count": 2, "weekdays": ["monday", "WE", "friday"]},
+	{"name": "Medicine", "person": "Bob", "boxes": ["morning", "evening"], "days": {"sunday": ["evening"], "saturday": []}}
+]`
+
+func TestParseTrackers(t *testing.T) {
+	trackers, err := ParseTrackers([]byte(testTrackers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trackers) != 3 {
+		t.Fatalf("expected 3 trackers, got %+v", trackers)
+	}
+	if tr := trackers[0]; len(tr.Boxes) != 1 || tr.Boxes[0] != "" || tr.Person != "" {
+		t.Errorf("expected one box without a label, got %+v", tr)
+	}
+	if tr := trackers[1]; len(tr.Boxes) != 2 || len(tr.Weekdays) != 3 || tr.Weekdays[1] != time.Wednesday {
+		t.Errorf("unexpected tracker: %+v", tr)
+	}
+	for _, s := range []string{`[{"person": "Bob"}]`, `[{"name": "Vitamins", "count": -1}]`, `[{"name": "Vitamins", "count": 2, "boxes": ["a"]}]`, `[{"name": "Vitamins", "weekdays": ["someday"]}]`} {
+		if _, err := ParseTrackers([]byte(s)); err == nil {
+			t.Errorf("expected an error for %s", s)
+		}
+	}
+}
+
+func TestAddTrackers(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trackers, err := ParseTrackers([]byte(testTrackers))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 2, Names: []string{"Alice", "Bob"}, SharedRow: "Home", Trackers: trackers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The tracker rows are below the rows of the people and the shared row
+	if s := strings.Join(page.Names, ","); s != "Alice,Bob,Home,Vitamins,Piano practice,Medicine" {
+		t.Fatalf("unexpected rows: %s", s)
+	}
+	if len(page.Legends) != 6 || len(page.Legends[3]) != 0 || page.Legends[4][0] != "Alice" || page.Legends[5][0] != "Bob" {
+		t.Errorf("unexpected legends: %q", page.Legends)
+	}
+	// The number of boxes each day, from monday to sunday
+	for row, want := range map[int]string{3: "1111111", 4: "2020200", 5: "2222201"} {
+		got := ""
+		for _, day := range page.Weeks[0].Days {
+			for _, line := range day.Cells[row].Lines {
+				if !line.Checkbox {
+					t.Errorf("expected a checkbox, got %+v", line)
+				}
+			}
+			got += string(rune('0' + len(day.Cells[row].Lines)))
+		}
+		if got != want {
+			t.Errorf("expected the boxes %s in the row %s, got %s", want, page.Names[row], got)
+		}
+	}
+	if line := page.Weeks[0].Days[6].Cells[5].Lines[0]; line.Text != "evening" {
+		t.Errorf("expected the evening box on sunday, got %+v", line)
+	}
+}
+
+func TestTrackersWithoutEvents(t *testing.T) {
+	cal, err := kal.NewCalendar("nb_NO", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := DefaultTemplate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trackers, err := ParseTrackers([]byte(`[{"name": "Vitamins"}, {"name": "Football"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, err := ParseRecurringEvents(strings.NewReader("SUMMARY:Waste\nDTSTART;VALUE=DATE:20250106\nRRULE:FREQ=WEEKLY\nROW:*\n\nSUMMARY:Match\nDTSTART;VALUE=DATE:20250107\nCATEGORIES:Football\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules, err := ParseEventRules("category:Football=*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, err := buildPage(cal, tmpl, Options{Year: 2025, Week: 2, Names: []string{"Alice", "Bob"}, Events: events, EventRules: rules, Trackers: trackers})
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday, tuesday := page.Weeks[0].Days[0], page.Weeks[0].Days[1]
+	if lines := monday.Cells[0].Lines; len(lines) != 1 || lines[0].Text != "Waste" {
+		t.Errorf("expected the event for all rows in the row of Alice, got %+v", lines)
+	}
+	if lines := tuesday.Cells[1].Lines; len(lines) != 1 || lines[0].Text != "Match" {
+		t.Errorf("expected the event of the * rule in the row of Bob, got %+v", lines)
+	}
+	// The tracker rows only have their boxes, also when a category has the name of a tracker row
+	for _, day := range []Day{monday, tuesday} {
+		for row := 2; row < 4; row++ {
+			if lines := day.Cells[row].Lines; len(lines) != 1 || lines[0].Text != "" || !lines[0].Checkbox {
+				t.Errorf("expected only a box in the row %s on %s, got %+v", page.Names[row], day.Date, lines)
+			}
+		}
+	}
+}